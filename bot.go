@@ -1,7 +1,10 @@
 package wee
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"io"
+	"os"
 	"time"
 
 	"github.com/coghost/xpretty"
@@ -28,12 +31,17 @@ type Bot struct {
 	// trackTime tracks the time spend on operation.
 	trackTime bool
 
+	// ctx is bound by WithContext, nil means context.Background().
+	ctx context.Context
+
 	// init behaviours
 	launcher *launcher.Launcher
 	browser  *rod.Browser
 	page     *rod.Page
 	prevPage *rod.Page
 	root     *rod.Element
+	// ownsBrowser is set when the browser is launched by the bot itself, not passed by Browser or Launcher.
+	ownsBrowser bool
 
 	left           int
 	windowMaximize bool
@@ -63,6 +71,32 @@ type Bot struct {
 	cookieFile   string
 	// copyAsCURLCookies reads `copy as cURL` directly from clipboard
 	copyAsCURLCookies []byte
+	// cookieKey encrypts cookie files at rest, see WithCookieKey.
+	cookieKey []byte
+	// pruneExpiredCookies drops expired cookies before they're set, see WithPruneExpiredCookies.
+	pruneExpiredCookies bool
+	// requiredCookies are checked after loaded, see WithRequiredCookies.
+	requiredCookies []string
+	cookieCheckHook CookieCheckHook
+	// hijacker is the single interception router shared with WithContext copies.
+	hijacker *hijackRouter
+	// har is the running HAR recorder, see StartHAR.
+	har *harState
+	// responseCache is enabled on page creation when responseCacheDir is set, see WithResponseCache.
+	responseCacheDir    string
+	responseCachePolicy *CachePolicy
+	responseCache       *ResponseCache
+	// networkLog* are set by WithNetworkLog or WithNetworkLogFile, netLog is the running logger.
+	networkLogWriter  io.Writer
+	networkLogFile    string
+	networkLogOptions []NetworkLogOptionFunc
+	netLog            *networkLogState
+	// networkProfile is emulated on page creation, see WithNetworkProfile.
+	networkProfile *NetworkProfile
+	// proxyAuth answers proxy auth challenges, see WithProxyAuth.
+	proxyAuth *proxyAuth
+	// storageStateFile saves cookies and web storage, restored on MustOpen.
+	storageStateFile string
 
 	// scrollHeight is the last valid height, use as fallback value.
 	scrollHeight float64
@@ -76,6 +110,9 @@ type Bot struct {
 
 	panicBy PanicByType
 
+	// timeouts is set by WithTimeouts, nil means NewTimeoutProfileDefault.
+	timeouts *TimeoutProfile
+
 	longTimeout   time.Duration
 	mediumTimeout time.Duration
 	shortTimeout  time.Duration
@@ -104,6 +141,27 @@ func NewBot(options ...BotOption) *Bot {
 	return bot
 }
 
+// NewBotE is the error-returning version of NewBot,
+// it never panics when the browser cannot be launched or the page cannot be set up.
+func NewBotE(options ...BotOption) (*Bot, error) {
+	bot := &Bot{withPageCreation: true}
+	bot.initialize()
+
+	bindBotOptions(bot, options...)
+
+	if err := resetIfHeadlessE(bot); err != nil {
+		bot.closeBrowser()
+		return nil, err
+	}
+
+	if err := bot.CustomizePageE(); err != nil {
+		bot.closeBrowser()
+		return nil, err
+	}
+
+	return bot, nil
+}
+
 // NewBotWithOptionsOnly creates a new Bot instance with options only, without creating a page.
 func NewBotWithOptionsOnly(options ...BotOption) *Bot {
 	options = append(options, WithPage(false))
@@ -113,42 +171,106 @@ func NewBotWithOptionsOnly(options ...BotOption) *Bot {
 // BindBotLanucher launches the browser and page for the bot.
 // This is used when we create a bot first and launch the browser elsewhere.
 func BindBotLanucher(bot *Bot, options ...BotOption) {
+	if err := BindBotLauncherE(bot, options...); err != nil {
+		panic(err)
+	}
+}
+
+// BindBotLauncherE is the error-returning version of BindBotLanucher.
+func BindBotLauncherE(bot *Bot, options ...BotOption) error {
 	if bot.isLaunched {
-		return
+		return nil
 	}
 
 	var (
 		lnchr *launcher.Launcher
 		brw   *rod.Browser
+		err   error
 	)
 
 	if bot.userMode {
 		if bot.forceCleanup {
-			err := ForceQuitBrowser(_browserChrome, 5) //nolint: mnd
-			if err != nil {
-				log.Fatalf("cannot close chrome: %v", err)
+			if err := ForceQuitBrowser(_browserChrome, 5); err != nil { //nolint: mnd
+				return fmt.Errorf("cannot close chrome: %w", err)
 			}
 		}
 
-		lnchr, brw = NewUserMode(LaunchLeakless(bot.forceCleanup), BrowserUserDataDir(bot.userDataDir))
+		lnchr, brw, err = NewUserModeE(LaunchLeakless(bot.forceCleanup), BrowserUserDataDir(bot.userDataDir))
 		// lnchr, brw = NewUserMode(bot.browserOptions...)
 	} else {
-		lnchr, brw = NewBrowser(bot.browserOptions...)
+		lnchr, brw, err = NewBrowserE(bot.browserOptions...)
+	}
+
+	if err != nil {
+		return err
 	}
 
 	options = append(options, Launcher(lnchr), Browser(brw), WithPage(true))
 	bindBotOptions(bot, options...)
 
-	resetIfHeadless(bot)
-	bot.CustomizePage()
+	if err := resetIfHeadlessE(bot); err != nil {
+		closeLaunched(lnchr, brw)
+		return err
+	}
+
+	if err := bot.CustomizePageE(); err != nil {
+		bot.closeBrowser()
+		closeLaunched(lnchr, brw)
+
+		return err
+	}
+
+	return nil
+}
+
+// closeBrowser stops proxy auth of a bot failed to be created, and closes the browser it launched,
+// the bot is not returned, so nothing else would close it. A browser passed in is left to the caller.
+func (b *Bot) closeBrowser() {
+	if b.browser == nil {
+		return
+	}
+
+	if err := b.hijacker.clearProxyAuth(b.browser); err != nil {
+		b.logger.Warn("cannot stop proxy auth", zap.Error(err))
+	}
+
+	if b.ownsBrowser {
+		closeLaunched(b.launcher, b.browser)
+	}
+}
+
+// closeLaunched closes brw and cleans l, closing them twice is harmless.
+func closeLaunched(l *launcher.Launcher, brw *rod.Browser) {
+	_proxyAuths.Delete(brw)
+	_hijackedBrowsers.Delete(brw)
+	_ = brw.Close()
+
+	if l != nil {
+		l.Cleanup()
+	}
 }
 
 func resetIfHeadless(bot *Bot) {
-	if bot.headless {
-		l, brw := NewBrowser(BrowserHeadless(bot.headless))
-		bot.launcher = l
-		bot.browser = brw
+	if err := resetIfHeadlessE(bot); err != nil {
+		panic(err)
+	}
+}
+
+func resetIfHeadlessE(bot *Bot) error {
+	if !bot.headless {
+		return nil
+	}
+
+	l, brw, err := NewBrowserE(BrowserHeadless(bot.headless))
+	if err != nil {
+		return err
 	}
+
+	bot.launcher = l
+	bot.browser = brw
+	bot.ownsBrowser = true
+
+	return nil
 }
 
 // NewBotDefault creates a bot with a default launcher/browser.
@@ -160,6 +282,25 @@ func NewBotDefault(options ...BotOption) *Bot {
 	return NewBot(options...)
 }
 
+// NewBotDefaultE is the error-returning version of NewBotDefault.
+func NewBotDefaultE(options ...BotOption) (*Bot, error) {
+	l, brw, err := NewBrowserE()
+	if err != nil {
+		return nil, err
+	}
+
+	options = append(options, Launcher(l), Browser(brw))
+
+	bot, err := NewBotE(options...)
+	if err != nil {
+		// NewBotE closes the bot's browser, which is another one in headless mode.
+		closeLaunched(l, brw)
+		return nil, err
+	}
+
+	return bot, nil
+}
+
 // NewBotHeadless sets headless with NewBotDefault
 func NewBotHeadless(options ...BotOption) *Bot {
 	options = append(options, Headless(true))
@@ -184,12 +325,39 @@ func NewBotUserMode(options ...BotOption) *Bot {
 	return NewBot(options...)
 }
 
+// NewBotUserModeE is the error-returning version of NewBotUserMode,
+// it returns ErrBrowserSessionBusy when chrome is already running.
+func NewBotUserModeE(options ...BotOption) (*Bot, error) {
+	l, brw, err := NewUserModeE()
+	if err != nil {
+		return nil, err
+	}
+
+	options = append(options, Launcher(l), Browser(brw), UserMode(true))
+
+	bot, err := NewBotE(options...)
+	if err != nil {
+		// NewBotE closes the bot's browser, which is another one in headless mode.
+		closeLaunched(l, brw)
+		return nil, err
+	}
+
+	return bot, nil
+}
+
 // initialize inits all attributes not exposed with options
 func (b *Bot) initialize() {
 	b.logger = zlog.MustNewZapLogger()
 
 	b.highlightTimes = 1
 	b.SetTimeout()
+	b.hijacker = newHijackRouter()
+	b.har = &harState{}
+	b.netLog = &networkLogState{}
+
+	if key := os.Getenv(CookieKeyEnv); key != "" {
+		b.cookieKey = []byte(key)
+	}
 	b.UniqueID = strutil.RandomCharsV3(_uniqueIDLen)
 }
 
@@ -206,14 +374,35 @@ func (b *Bot) Cleanup() {
 		return
 	}
 
+	// flush requests in flight and close the log file.
+	if err := b.StopNetworkLog(); err != nil {
+		b.logger.Warn("cannot stop network log", zap.Error(err))
+	}
+
+	// before StopHijack, the router is kept running while it answers proxy auth challenges.
+	if err := b.hijacker.clearProxyAuth(b.browser); err != nil {
+		b.logger.Warn("cannot stop proxy auth", zap.Error(err))
+	}
+
 	// non user mode, close and clean.
 	if !b.userMode {
+		_proxyAuths.Delete(b.browser)
+		_hijackedBrowsers.Delete(b.browser)
 		b.browser.MustClose()
 		b.launcher.Cleanup()
 
 		return
 	}
 
+	// the browser is kept, so interception must not outlive the bot.
+	if err := b.responseCache.Close(); err != nil {
+		b.logger.Warn("cannot close response cache", zap.Error(err))
+	}
+
+	if err := b.StopHijack(); err != nil {
+		b.logger.Warn("cannot stop hijack", zap.Error(err))
+	}
+
 	// by default is not force mode, just return.
 	if !b.forceCleanup {
 		b.logger.Info("runs in user mode, skip cleanup browser, you should call `bot.Page().Close()` manually.")
@@ -241,11 +430,20 @@ func (b *Bot) ClearStorageCookies() {
 	}
 }
 
+// SetTimeout resets all timeouts to the profile set by WithTimeouts,
+// or to the package defaults (LongToSec, MediumToSec, ShortToSec, NapToSec) if not set.
 func (b *Bot) SetTimeout() {
-	b.longTimeout = LongToSec * time.Second
-	b.mediumTimeout = MediumToSec * time.Second
-	b.shortTimeout = ShortToSec * time.Second
-	b.napTimeout = NapToSec * time.Second
+	dft := NewTimeoutProfileDefault()
+
+	p := dft
+	if b.timeouts != nil {
+		p = b.timeouts
+	}
+
+	b.longTimeout = durationAorB(p.Long, dft.Long)
+	b.mediumTimeout = durationAorB(p.Medium, dft.Medium)
+	b.shortTimeout = durationAorB(p.Short, dft.Short)
+	b.napTimeout = durationAorB(p.Nap, dft.Nap)
 	b.pt10s = PT10Sec * time.Second
 	b.pt1s = 1 * time.Second
 }
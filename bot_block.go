@@ -0,0 +1,410 @@
+package wee
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// BlockResourcesPriority is the priority of the blocking rule,
+// it's higher than the default one, so blocked requests are never cached, replayed or loaded.
+const BlockResourcesPriority = 100
+
+// BlockProfile is a set of requests to block, see BlockResources.
+type BlockProfile struct {
+	// ResourceTypes are blocked for all domains.
+	ResourceTypes []proto.NetworkResourceType
+	// Domains are blocked with their subdomains, e.g. "doubleclick.net" blocks "ad.doubleclick.net".
+	Domains []string
+	// Patterns are url globs like proto.FetchRequestPattern.URLPattern, e.g. "*/ads/*".
+	Patterns []string
+	// AllowDomains are never blocked, with their subdomains, e.g. from "@@||cdn.example.com^".
+	AllowDomains []string
+}
+
+// _trackerDomains are common ad and analytics domains.
+var _trackerDomains = []string{
+	"2mdn.net",
+	"adnxs.com",
+	"adsrvr.org",
+	"amazon-adsystem.com",
+	"amplitude.com",
+	"bat.bing.com",
+	"chartbeat.com",
+	"clarity.ms",
+	"connect.facebook.net",
+	"criteo.com",
+	"criteo.net",
+	"doubleclick.net",
+	"google-analytics.com",
+	"googleadservices.com",
+	"googlesyndication.com",
+	"googletagmanager.com",
+	"googletagservices.com",
+	"hotjar.com",
+	"mc.yandex.ru",
+	"mixpanel.com",
+	"moatads.com",
+	"nr-data.net",
+	"openx.net",
+	"outbrain.com",
+	"pubmatic.com",
+	"quantserve.com",
+	"rubiconproject.com",
+	"scorecardresearch.com",
+	"segment.io",
+	"taboola.com",
+}
+
+// NewBlockProfileImages blocks images, the same as DisableImages.
+func NewBlockProfileImages() *BlockProfile {
+	return &BlockProfile{ResourceTypes: []proto.NetworkResourceType{proto.NetworkResourceTypeImage}}
+}
+
+// NewBlockProfileMedia blocks audio and video.
+func NewBlockProfileMedia() *BlockProfile {
+	return &BlockProfile{ResourceTypes: []proto.NetworkResourceType{proto.NetworkResourceTypeMedia}}
+}
+
+// NewBlockProfileFonts blocks web fonts.
+func NewBlockProfileFonts() *BlockProfile {
+	return &BlockProfile{ResourceTypes: []proto.NetworkResourceType{proto.NetworkResourceTypeFont}}
+}
+
+// NewBlockProfileTrackers blocks common ad and analytics domains.
+func NewBlockProfileTrackers() *BlockProfile {
+	return &BlockProfile{Domains: append([]string(nil), _trackerDomains...)}
+}
+
+// NewBlockProfileTextOnly keeps html, scripts and api calls, everything else and trackers are blocked.
+func NewBlockProfileTextOnly() *BlockProfile {
+	return NewBlockProfileImages().Merge(
+		NewBlockProfileMedia(),
+		NewBlockProfileFonts(),
+		NewBlockProfileTrackers(),
+		&BlockProfile{ResourceTypes: []proto.NetworkResourceType{proto.NetworkResourceTypeStylesheet}},
+	)
+}
+
+// Merge returns a new profile blocking everything blocked by p and others.
+func (p *BlockProfile) Merge(others ...*BlockProfile) *BlockProfile {
+	merged := &BlockProfile{}
+
+	for _, o := range append([]*BlockProfile{p}, others...) {
+		if o == nil {
+			continue
+		}
+
+		merged.ResourceTypes = append(merged.ResourceTypes, o.ResourceTypes...)
+		merged.Domains = append(merged.Domains, o.Domains...)
+		merged.Patterns = append(merged.Patterns, o.Patterns...)
+		merged.AllowDomains = append(merged.AllowDomains, o.AllowDomains...)
+	}
+
+	return merged
+}
+
+// LoadBlocklist reads a blocklist file into a profile.
+//
+// Supported lines:
+//   - EasyList network rules: "||ads.example.com^", "@@||cdn.example.com^", "|https://example.com/ads/", "/banner/*.gif",
+//     options after "$" are ignored.
+//   - hosts file: "0.0.0.0 ads.example.com".
+//   - plain domains: "ads.example.com".
+//
+// Comments ("!" or "#"), "[Adblock Plus ...]" headers and element hiding rules ("##") are skipped.
+func LoadBlocklist(path string) (*BlockProfile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load blocklist: %w", err)
+	}
+
+	return ParseBlocklist(raw), nil
+}
+
+// ParseBlocklist parses blocklist content, see LoadBlocklist.
+func ParseBlocklist(raw []byte) *BlockProfile {
+	p := &BlockProfile{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "",
+			strings.HasPrefix(line, "!"),
+			strings.HasPrefix(line, "#"),
+			strings.HasPrefix(line, "["),
+			strings.Contains(line, "##"),
+			strings.Contains(line, "#@#"):
+			continue
+		}
+
+		p.addRule(line)
+	}
+
+	return p
+}
+
+func (p *BlockProfile) addRule(line string) {
+	// hosts file, e.g. "0.0.0.0 ads.example.com # comment"
+	if fields := strings.Fields(line); len(fields) > 1 && net.ParseIP(fields[0]) != nil {
+		if fields[1] != "localhost" {
+			p.Domains = append(p.Domains, fields[1])
+		}
+
+		return
+	}
+
+	allow := strings.HasPrefix(line, "@@")
+	line = strings.TrimPrefix(line, "@@")
+	line, _, _ = strings.Cut(line, "$")
+
+	if domain, ok := blocklistDomain(line); ok {
+		if allow {
+			p.AllowDomains = append(p.AllowDomains, domain)
+		} else {
+			p.Domains = append(p.Domains, domain)
+		}
+
+		return
+	}
+
+	// exceptions of url rules are not supported.
+	if allow || line == "" {
+		return
+	}
+
+	p.Patterns = append(p.Patterns, blocklistPattern(line))
+}
+
+// blocklistDomain returns the domain of rules like "||example.com^" or "example.com".
+func blocklistDomain(rule string) (string, bool) {
+	domain := strings.TrimSuffix(strings.TrimPrefix(rule, "||"), "^")
+	if domain == "" || strings.ContainsAny(domain, "/*^|:?=") || !strings.Contains(domain, ".") {
+		return "", false
+	}
+
+	return strings.ToLower(domain), true
+}
+
+// blocklistPattern converts an EasyList url rule to a glob.
+func blocklistPattern(rule string) string {
+	ptn := rule
+
+	switch {
+	case strings.HasPrefix(ptn, "||"):
+		// domain anchor, matches any scheme and subdomain.
+		ptn = "*" + strings.TrimPrefix(ptn, "||")
+	case strings.HasPrefix(ptn, "|"):
+		ptn = strings.TrimPrefix(ptn, "|")
+	default:
+		ptn = "*" + ptn
+	}
+
+	if strings.HasSuffix(ptn, "|") {
+		ptn = strings.TrimSuffix(ptn, "|")
+	} else {
+		ptn += "*"
+	}
+
+	// "^" is a separator like "/" or "?", a wildcard is close enough.
+	return strings.ReplaceAll(ptn, "^", "*")
+}
+
+// BlockStats counts requests blocked by a ResourceBlocker.
+type BlockStats struct {
+	Total  int
+	ByType map[proto.NetworkResourceType]int
+	ByHost map[string]int
+}
+
+// ResourceBlocker is returned by BlockResources, it counts blocked requests until removed.
+type ResourceBlocker struct {
+	handle *HijackRuleHandle
+
+	types   map[proto.NetworkResourceType]bool
+	domains domainSet
+	allowed domainSet
+	// patterns are only for url rules, domains are looked up in the sets.
+	patterns []*regexp.Regexp
+
+	mu    sync.Mutex
+	stats BlockStats
+}
+
+// BlockResources fails requests matched by any of profiles with net::ERR_BLOCKED_BY_CLIENT,
+// to save bandwidth of proxies and speed up page loads.
+//
+// Built-in profiles are NewBlockProfileImages, NewBlockProfileMedia, NewBlockProfileFonts,
+// NewBlockProfileTrackers and NewBlockProfileTextOnly, user blocklists are loaded by LoadBlocklist.
+// Navigation requests of main frame and iframes are never blocked.
+//
+// Usage:
+//
+//	list, err := wee.LoadBlocklist("easylist.txt")
+//	blocker := bot.MustBlockResources(wee.NewBlockProfileTextOnly(), list)
+//	defer blocker.Remove()
+//
+//	bot.MustOpen(uri)
+//	fmt.Println(blocker.Stats().Total)
+func (b *Bot) BlockResources(profiles ...*BlockProfile) (*ResourceBlocker, error) {
+	blocker, err := newResourceBlocker((&BlockProfile{}).Merge(profiles...))
+	if err != nil {
+		return nil, err
+	}
+
+	handle, err := b.AddHijackRule(HijackRule{
+		Priority: BlockResourcesPriority,
+		Match: func(ctx *rod.Hijack) bool {
+			return blocker.blocks(ctx.Request.URL(), ctx.Request.Type())
+		},
+		Handler: blocker.block,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	blocker.handle = handle
+
+	return blocker, nil
+}
+
+func newResourceBlocker(profile *BlockProfile) (*ResourceBlocker, error) {
+	blocker := &ResourceBlocker{
+		types:   make(map[proto.NetworkResourceType]bool),
+		domains: newDomainSet(profile.Domains),
+		allowed: newDomainSet(profile.AllowDomains),
+		stats: BlockStats{
+			ByType: make(map[proto.NetworkResourceType]int),
+			ByHost: make(map[string]int),
+		},
+	}
+
+	for _, t := range profile.ResourceTypes {
+		blocker.types[t] = true
+	}
+
+	for _, ptn := range profile.Patterns {
+		reg, err := regexp.Compile(proto.PatternToReg(ptn))
+		if err != nil {
+			return nil, fmt.Errorf("invalid block pattern %q: %w", ptn, err)
+		}
+
+		blocker.patterns = append(blocker.patterns, reg)
+	}
+
+	return blocker, nil
+}
+
+func (b *Bot) MustBlockResources(profiles ...*BlockProfile) *ResourceBlocker {
+	blocker, err := b.BlockResources(profiles...)
+	b.pie(err)
+
+	return blocker
+}
+
+// Remove stops blocking, counters are kept.
+func (r *ResourceBlocker) Remove() error {
+	if r == nil {
+		return nil
+	}
+
+	return r.handle.Remove()
+}
+
+// Stats returns a copy of counters of blocked requests.
+func (r *ResourceBlocker) Stats() BlockStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := BlockStats{
+		Total:  r.stats.Total,
+		ByType: make(map[proto.NetworkResourceType]int, len(r.stats.ByType)),
+		ByHost: make(map[string]int, len(r.stats.ByHost)),
+	}
+
+	for k, v := range r.stats.ByType {
+		stats.ByType[k] = v
+	}
+
+	for k, v := range r.stats.ByHost {
+		stats.ByHost[k] = v
+	}
+
+	return stats
+}
+
+func (r *ResourceBlocker) block(ctx *rod.Hijack) {
+	r.mu.Lock()
+	r.stats.Total++
+	r.stats.ByType[ctx.Request.Type()]++
+	r.stats.ByHost[ctx.Request.URL().Hostname()]++
+	r.mu.Unlock()
+
+	ctx.Response.Fail(proto.NetworkErrorReasonBlockedByClient)
+}
+
+func (r *ResourceBlocker) blocks(u *url.URL, typ proto.NetworkResourceType) bool {
+	if typ == proto.NetworkResourceTypeDocument {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if r.allowed.matches(host) {
+		return false
+	}
+
+	if r.types[typ] || r.domains.matches(host) {
+		return true
+	}
+
+	uri := u.String()
+	for _, reg := range r.patterns {
+		if reg.MatchString(uri) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// domainSet is an index of lowercase domains, blocklists have tens of thousands of them.
+type domainSet map[string]struct{}
+
+func newDomainSet(domains []string) domainSet {
+	set := make(domainSet, len(domains))
+	for _, d := range domains {
+		set[strings.ToLower(d)] = struct{}{}
+	}
+
+	return set
+}
+
+// matches returns true if host is one of the domains or their subdomains,
+// host and its parent domains are looked up label by label, e.g. "a.b.com", "b.com", "com".
+func (s domainSet) matches(host string) bool {
+	for host != "" {
+		if _, ok := s[host]; ok {
+			return true
+		}
+
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			return false
+		}
+
+		host = parent
+	}
+
+	return false
+}
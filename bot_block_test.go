@@ -0,0 +1,108 @@
+package wee
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/coghost/wee/fixtures"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/suite"
+)
+
+type BlockSuite struct {
+	suite.Suite
+}
+
+func TestBlock(t *testing.T) {
+	suite.Run(t, new(BlockSuite))
+}
+
+func (s *BlockSuite) TestParseBlocklist() {
+	raw := []byte(`[Adblock Plus 2.0]
+! Title: test list
+||Ads.Example.com^
+||tracker.net^$third-party
+@@||cdn.ads.example.com^
+|https://example.com/promo/
+/banner/*.gif
+example.com##.ad-box
+# hosts file
+0.0.0.0 metrics.example.org
+127.0.0.1 localhost
+plain.example.io
+`)
+
+	p := ParseBlocklist(raw)
+
+	s.Equal([]string{"ads.example.com", "tracker.net", "metrics.example.org", "plain.example.io"}, p.Domains)
+	s.Equal([]string{"cdn.ads.example.com"}, p.AllowDomains)
+	s.Equal([]string{"https://example.com/promo/*", "*/banner/*.gif*"}, p.Patterns)
+}
+
+func (s *BlockSuite) TestBlocks() {
+	list := ParseBlocklist([]byte("||ads.example.com^\n@@||cdn.ads.example.com^\n/banner/*.gif\n"))
+
+	list.ResourceTypes = []proto.NetworkResourceType{proto.NetworkResourceTypeFont}
+
+	blocker, err := newResourceBlocker(list)
+	s.Require().NoError(err)
+	s.Len(blocker.patterns, 1, "domain rules are not patterns")
+
+	cases := []struct {
+		uri  string
+		typ  proto.NetworkResourceType
+		want bool
+	}{
+		{"https://x.ads.example.com/a.js", proto.NetworkResourceTypeScript, true},
+		{"https://cdn.ads.example.com/a.js", proto.NetworkResourceTypeScript, false},
+		{"https://ads.example.com/", proto.NetworkResourceTypeDocument, false},
+		{"https://site.com/banner/top.gif", proto.NetworkResourceTypeImage, true},
+		{"https://site.com/font.woff2", proto.NetworkResourceTypeFont, true},
+		{"https://site.com/app.js", proto.NetworkResourceTypeScript, false},
+		{"https://notads.example.com.evil/a.js", proto.NetworkResourceTypeScript, false},
+		{"https://notads.example.com/a.js", proto.NetworkResourceTypeScript, false},
+		{"https://X.Ads.Example.com/a.js", proto.NetworkResourceTypeScript, true},
+	}
+
+	for _, c := range cases {
+		u, err := url.Parse(c.uri)
+		s.Require().NoError(err)
+		s.Equal(c.want, blocker.blocks(u, c.typ), c.uri)
+	}
+}
+
+func (s *BlockSuite) TestProfiles() {
+	p := NewBlockProfileTextOnly()
+
+	s.ElementsMatch([]proto.NetworkResourceType{
+		proto.NetworkResourceTypeImage,
+		proto.NetworkResourceTypeMedia,
+		proto.NetworkResourceTypeFont,
+		proto.NetworkResourceTypeStylesheet,
+	}, p.ResourceTypes)
+	s.Contains(p.Domains, "google-analytics.com")
+
+	// profiles are copies, changing one doesn't change the built-in list.
+	NewBlockProfileTrackers().Domains[0] = "changed"
+	s.NotEqual("changed", NewBlockProfileTrackers().Domains[0])
+}
+
+func (s *BlockSuite) TestBot() {
+	ts := fixtures.NewTestServer()
+	defer ts.Close()
+
+	bot := NewBotHeadless()
+	defer bot.Cleanup()
+
+	blocker := bot.MustBlockResources(NewBlockProfileImages(), &BlockProfile{Patterns: []string{"*test-script.js?resource=blocked*"}})
+	defer blocker.Remove()
+
+	bot.MustOpen(ts.URL + "/hijack_test?resource=blocked")
+	bot.page.MustWaitRequestIdle()
+
+	stats := blocker.Stats()
+	s.Equal(2, stats.Total)
+	s.Equal(1, stats.ByType[proto.NetworkResourceTypeImage])
+	s.Equal(1, stats.ByType[proto.NetworkResourceTypeScript])
+	s.Equal(2, stats.ByHost["127.0.0.1"])
+}
@@ -206,7 +206,7 @@ func (b *Bot) ClickWithScript(selector string, opts ...ElemOptionFunc) error {
 //  3. Checks if the element is still interactable after the click.
 //
 // Options:
-//   - timeout: Duration to wait for the click operation to complete (default: MediumToSec).
+//   - timeout: Duration to wait for the click operation to complete (default: the bot's medium timeout).
 //   - highlight: If true, highlights the element before clicking (default: true).
 //
 // Note:
@@ -215,14 +215,14 @@ func (b *Bot) ClickWithScript(selector string, opts ...ElemOptionFunc) error {
 //   - If the element becomes non-interactable (e.g., removed from DOM) after the click,
 //     it's considered a successful operation, assuming the click caused a page change.
 func (b *Bot) ClickElemWithScript(elem *rod.Element, opts ...ElemOptionFunc) error {
-	opt := ElemOptions{timeout: MediumToSec, highlight: true}
+	opt := ElemOptions{timeout: b.mediumTimeout.Seconds(), highlight: true}
 	bindElemOptions(&opt, opts...)
 
 	if opt.highlight {
 		b.FocusAndHighlight(elem)
 	}
 
-	_, err := elem.Timeout(time.Duration(opt.timeout)*time.Second).CancelTimeout().Eval(`(elem) => { this.click() }`, elem)
+	_, err := elem.Timeout(secToDuration(opt.timeout)).CancelTimeout().Eval(`(elem) => { this.click() }`, elem)
 	if err != nil {
 		b.logger.Error("cannot close by Eval script this.click()", zap.Error(err), zap.String("elem", elem.String()))
 		return err
@@ -370,7 +370,7 @@ func (b *Bot) ClosePopover(sel string) (int, error) {
 func (b *Bot) MustClickSequentially(selectors ...string) {
 	for _, sel := range selectors {
 		b.MustClick(sel)
-		SleepPT500Ms()
+		b.pie(b.sleepPT500Ms())
 	}
 
 	b.MustDOMStable()
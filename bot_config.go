@@ -0,0 +1,339 @@
+package wee
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ConfigFormatJSON = "json"
+	ConfigFormatYAML = "yaml"
+)
+
+var (
+	ErrUnknownConfigFormat = errors.New("unknown config format")
+	ErrInvalidConfig       = errors.New("invalid bot config")
+)
+
+var _panicByNames = map[string]PanicByType{
+	"":          PanicByDft,
+	"default":   PanicByDft,
+	"dump":      PanicByDump,
+	"log_error": PanicByLogError,
+}
+
+// Config is the declarative form of BotOption and BrowserOptionFunc,
+// it can be loaded from a YAML or JSON file with LoadConfig.
+//
+// Example (yaml):
+//
+//	headless: true
+//	humanized: true
+//	cookies: true
+//	cookie_folder: /data/cookies
+//	popovers: ["div.modal button.close"]
+//	timeouts: {long: 120, medium: 40, short: 20, nap: 4}
+//	browser:
+//	  proxy: http://127.0.0.1:8080
+//	  flags: ["disable-gpu"]
+type Config struct {
+	Headless       bool   `json:"headless"        yaml:"headless"`
+	UserMode       bool   `json:"user_mode"       yaml:"user_mode"`
+	UserDataDir    string `json:"user_data_dir"   yaml:"user_data_dir"`
+	UserAgent      string `json:"user_agent"      yaml:"user_agent"`
+	AcceptLanguage string `json:"accept_language" yaml:"accept_language"`
+
+	Humanized    bool `json:"humanized"     yaml:"humanized"`
+	Stealth      bool `json:"stealth"       yaml:"stealth"`
+	ForceCleanup bool `json:"force_cleanup" yaml:"force_cleanup"`
+	ClearCookies bool `json:"clear_cookies" yaml:"clear_cookies"`
+	TrackTime    bool `json:"track_time"    yaml:"track_time"`
+
+	// HighlightTimes is a pointer, so 0 (disable highlight) can be told apart from unset.
+	HighlightTimes *int `json:"highlight_times" yaml:"highlight_times"`
+	// PanicBy is one of "default", "dump" or "log_error".
+	PanicBy  string   `json:"panic_by" yaml:"panic_by"`
+	Popovers []string `json:"popovers" yaml:"popovers"`
+
+	Cookies      bool   `json:"cookies"       yaml:"cookies"`
+	CookieFolder string `json:"cookie_folder" yaml:"cookie_folder"`
+	CookieFile   string `json:"cookie_file"   yaml:"cookie_file"`
+	StorageState string `json:"storage_state" yaml:"storage_state"`
+	// PruneExpiredCookies and RequiredCookies, a missing or expired required cookie is logged as warning.
+	PruneExpiredCookies bool     `json:"prune_expired_cookies" yaml:"prune_expired_cookies"`
+	RequiredCookies     []string `json:"required_cookies"      yaml:"required_cookies"`
+	// NetworkLog is the JSONL file of all requests, see WithNetworkLogFile.
+	NetworkLog string `json:"network_log" yaml:"network_log"`
+
+	WindowMaximize bool           `json:"window_maximize" yaml:"window_maximize"`
+	LeftPosition   int            `json:"left_position"   yaml:"left_position"`
+	Bounds         *BrowserBounds `json:"bounds"          yaml:"bounds"`
+
+	Timeouts *TimeoutsConfig `json:"timeouts" yaml:"timeouts"`
+	Browser  BrowserConfig   `json:"browser"  yaml:"browser"`
+}
+
+// TimeoutsConfig is the TimeoutProfile in seconds, zero values are kept as default.
+type TimeoutsConfig struct {
+	Long   float64 `json:"long"   yaml:"long"`
+	Medium float64 `json:"medium" yaml:"medium"`
+	Short  float64 `json:"short"  yaml:"short"`
+	Nap    float64 `json:"nap"    yaml:"nap"`
+}
+
+// BrowserConfig is the declarative form of BrowserOptionFunc.
+type BrowserConfig struct {
+	Proxy            string   `json:"proxy"              yaml:"proxy"`
+	Extensions       []string `json:"extensions"         yaml:"extensions"`
+	Flags            []string `json:"flags"              yaml:"flags"`
+	PaintRects       bool     `json:"paint_rects"        yaml:"paint_rects"`
+	Incognito        bool     `json:"incognito"          yaml:"incognito"`
+	IgnoreCertErrors bool     `json:"ignore_cert_errors" yaml:"ignore_cert_errors"`
+	Leakless         bool     `json:"leakless"           yaml:"leakless"`
+	// SlowMotionDelay in milliseconds, 0 means SlowMotionMillis.
+	SlowMotionDelay int `json:"slow_motion_delay" yaml:"slow_motion_delay"`
+}
+
+// LoadConfig reads a bot config from path, the format is chosen by file extension (.json, .yaml, .yml).
+// Unknown keys are reported as errors.
+func LoadConfig(path string) (*Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read config: %w", err)
+	}
+
+	var format string
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		format = ConfigFormatJSON
+	case ".yaml", ".yml":
+		format = ConfigFormatYAML
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownConfigFormat, path)
+	}
+
+	return ParseConfig(raw, format)
+}
+
+// ParseConfig parses raw in format (ConfigFormatJSON or ConfigFormatYAML) and validates the result.
+func ParseConfig(raw []byte, format string) (*Config, error) {
+	cfg := &Config{}
+
+	switch format {
+	case ConfigFormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+	case ConfigFormatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(raw))
+		dec.KnownFields(true)
+
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownConfigFormat, format)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks values which cannot be caught by decoding.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if _, ok := _panicByNames[c.PanicBy]; !ok {
+		errs = append(errs, fmt.Errorf("panic_by: unknown value %q", c.PanicBy))
+	}
+
+	if c.HighlightTimes != nil && *c.HighlightTimes < 0 {
+		errs = append(errs, fmt.Errorf("highlight_times: must not be negative, got %d", *c.HighlightTimes))
+	}
+
+	if t := c.Timeouts; t != nil && (t.Long < 0 || t.Medium < 0 || t.Short < 0 || t.Nap < 0) {
+		errs = append(errs, errors.New("timeouts: must not be negative"))
+	}
+
+	if b := c.Bounds; b != nil && (b.Width <= 0 || b.Height <= 0) {
+		errs = append(errs, fmt.Errorf("bounds: width and height must be positive, got %dx%d", b.Width, b.Height))
+	}
+
+	if c.Browser.SlowMotionDelay < 0 {
+		errs = append(errs, fmt.Errorf("browser.slow_motion_delay: must not be negative, got %d", c.Browser.SlowMotionDelay))
+	}
+
+	if c.Browser.Proxy != "" && c.UserMode {
+		errs = append(errs, errors.New("browser.proxy: not supported in user_mode"))
+	}
+
+	if len(errs) != 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// BrowserOptions converts the config to BrowserOptionFunc.
+func (c *Config) BrowserOptions() []BrowserOptionFunc {
+	brw := c.Browser
+
+	opts := []BrowserOptionFunc{
+		BrowserHeadless(c.Headless),
+		BrowserPaintRects(brw.PaintRects),
+		BrowserIncognito(brw.Incognito),
+		BrowserIgnoreCertErrors(brw.IgnoreCertErrors),
+		LaunchLeakless(brw.Leakless),
+		BrowserSlowMotionDelay(IntAorB(brw.SlowMotionDelay, SlowMotionMillis)),
+	}
+
+	if c.UserDataDir != "" {
+		opts = append(opts, BrowserUserDataDir(c.UserDataDir))
+	}
+
+	if brw.Proxy != "" {
+		opts = append(opts, BrowserProxy(brw.Proxy))
+	}
+
+	if len(brw.Extensions) != 0 {
+		opts = append(opts, BrowserExtensions(brw.Extensions...))
+	}
+
+	if len(brw.Flags) != 0 {
+		opts = append(opts, BrowserFlags(brw.Flags...))
+	}
+
+	return opts
+}
+
+// BotOptions converts the config to BotOption.
+//
+// Headless is not included, it is applied to the browser launched by NewBotFromConfig,
+// otherwise the bot would relaunch a bare headless browser and drop the browser options.
+func (c *Config) BotOptions() []BotOption {
+	opts := []BotOption{
+		UserMode(c.UserMode),
+		Humanized(c.Humanized),
+		StealthMode(c.Stealth),
+		ForceCleanup(c.ForceCleanup),
+		ClearCookies(c.ClearCookies),
+		TrackTime(c.TrackTime),
+		WithPanicBy(_panicByNames[c.PanicBy]),
+		WithCookies(c.Cookies),
+		WindowMaximize(c.WindowMaximize),
+		WithLeftPosition(c.LeftPosition),
+		WithBrowserOptions(c.BrowserOptions()),
+	}
+
+	if c.UserDataDir != "" {
+		opts = append(opts, UserDataDir(c.UserDataDir))
+	}
+
+	if c.UserAgent != "" {
+		opts = append(opts, UserAgent(c.UserAgent))
+	}
+
+	if c.AcceptLanguage != "" {
+		opts = append(opts, AcceptLanguage(c.AcceptLanguage))
+	}
+
+	if c.HighlightTimes != nil {
+		opts = append(opts, WithHighlightTimes(*c.HighlightTimes))
+	}
+
+	if len(c.Popovers) != 0 {
+		opts = append(opts, WithPopovers(c.Popovers...))
+	}
+
+	if c.CookieFolder != "" {
+		opts = append(opts, WithCookieFolder(c.CookieFolder))
+	}
+
+	if c.CookieFile != "" {
+		opts = append(opts, WithCookieFile(c.CookieFile))
+	}
+
+	if c.StorageState != "" {
+		opts = append(opts, WithStorageState(c.StorageState))
+	}
+
+	if c.PruneExpiredCookies {
+		opts = append(opts, WithPruneExpiredCookies(true))
+	}
+
+	if len(c.RequiredCookies) != 0 {
+		opts = append(opts, WithRequiredCookies(nil, c.RequiredCookies...))
+	}
+
+	if c.NetworkLog != "" {
+		opts = append(opts, WithNetworkLogFile(c.NetworkLog))
+	}
+
+	if c.Bounds != nil {
+		opts = append(opts, WithBounds(c.Bounds))
+	}
+
+	if t := c.Timeouts; t != nil {
+		opts = append(opts, WithTimeouts(NewTimeoutProfile(t.Long, t.Medium, t.Short, t.Nap)))
+	}
+
+	return opts
+}
+
+// NewBotFromConfig launches a browser (or connects the system chrome in user mode)
+// and creates a bot with all options from cfg.
+//
+// Extra options are applied after the config, so they take precedence.
+func NewBotFromConfig(cfg *Config, options ...BotOption) (*Bot, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	brwOpts := cfg.BrowserOptions()
+
+	launch := NewBrowserE
+	if cfg.UserMode {
+		launch = NewUserModeE
+	}
+
+	l, brw, err := launch(brwOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := append(cfg.BotOptions(), Launcher(l), Browser(brw))
+	opts = append(opts, options...)
+
+	bot, err := NewBotE(opts...)
+	if err != nil {
+		closeLaunched(l, brw)
+		return nil, err
+	}
+
+	return bot, nil
+}
+
+// NewBotFromConfigFile is LoadConfig followed by NewBotFromConfig.
+func NewBotFromConfigFile(path string, options ...BotOption) (*Bot, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBotFromConfig(cfg, options...)
+}
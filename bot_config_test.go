@@ -0,0 +1,101 @@
+package wee
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type BotConfigSuite struct {
+	suite.Suite
+}
+
+func TestBotConfig(t *testing.T) {
+	suite.Run(t, new(BotConfigSuite))
+}
+
+func (s *BotConfigSuite) TestParseYAMLAndJSON() {
+	yml := `
+headless: true
+humanized: true
+highlight_times: 0
+panic_by: log_error
+cookies: true
+cookie_folder: /tmp/cookies
+popovers: ["div.modal"]
+bounds: {width: 1280, height: 720}
+timeouts: {long: 120, short: 20}
+browser:
+  proxy: http://127.0.0.1:8080
+  flags: ["disable-gpu"]
+`
+	js := `{
+  "headless": true, "humanized": true, "highlight_times": 0, "panic_by": "log_error",
+  "cookies": true, "cookie_folder": "/tmp/cookies", "popovers": ["div.modal"],
+  "bounds": {"width": 1280, "height": 720}, "timeouts": {"long": 120, "short": 20},
+  "browser": {"proxy": "http://127.0.0.1:8080", "flags": ["disable-gpu"]}
+}`
+
+	fromYAML, err := ParseConfig([]byte(yml), ConfigFormatYAML)
+	s.Require().NoError(err)
+
+	fromJSON, err := ParseConfig([]byte(js), ConfigFormatJSON)
+	s.Require().NoError(err)
+
+	s.Equal(fromJSON, fromYAML)
+
+	bot := &Bot{}
+	bot.SetTimeout()
+	bindBotOptions(bot, fromYAML.BotOptions()...)
+
+	s.True(bot.humanized)
+	s.Equal(0, bot.highlightTimes)
+	s.Equal(PanicByLogError, bot.panicBy)
+	s.Equal("/tmp/cookies", bot.cookieFolder)
+	s.Equal([]string{"div.modal"}, bot.popovers)
+	s.Equal(1280, bot.bounds.Width)
+	s.Equal(120*time.Second, bot.longTimeout)
+	s.Equal(MediumToSec*time.Second, bot.mediumTimeout)
+
+	opt := BrowserOptions{}
+	bindBrowserOptions(&opt, fromYAML.BrowserOptions()...)
+	s.True(opt.headless)
+	s.Equal("http://127.0.0.1:8080", opt.proxy)
+	s.Equal([]string{"disable-gpu"}, opt.flags)
+	s.Equal(SlowMotionMillis, opt.slowMotionDelay)
+}
+
+func (s *BotConfigSuite) TestUnknownKeys() {
+	_, err := ParseConfig([]byte("headles: true"), ConfigFormatYAML)
+	s.ErrorIs(err, ErrInvalidConfig)
+
+	_, err = ParseConfig([]byte(`{"browser": {"proxyy": "x"}}`), ConfigFormatJSON)
+	s.ErrorIs(err, ErrInvalidConfig)
+}
+
+func (s *BotConfigSuite) TestValidate() {
+	_, err := ParseConfig([]byte("panic_by: exit\nbounds: {width: 0, height: 10}"), ConfigFormatYAML)
+	s.ErrorIs(err, ErrInvalidConfig)
+	s.Contains(err.Error(), "panic_by")
+	s.Contains(err.Error(), "bounds")
+}
+
+func (s *BotConfigSuite) TestLoadConfig() {
+	dir := s.T().TempDir()
+
+	file := filepath.Join(dir, "bot.yml")
+	s.Require().NoError(os.WriteFile(file, []byte("stealth: true"), 0o600))
+
+	cfg, err := LoadConfig(file)
+	s.Require().NoError(err)
+	s.True(cfg.Stealth)
+
+	file = filepath.Join(dir, "bot.toml")
+	s.Require().NoError(os.WriteFile(file, []byte(""), 0o600))
+
+	_, err = LoadConfig(file)
+	s.ErrorIs(err, ErrUnknownConfigFormat)
+}
@@ -0,0 +1,75 @@
+package wee
+
+import (
+	"context"
+
+	"github.com/go-rod/rod"
+)
+
+// WithContext returns a shallow copy of the bot bound to ctx.
+//
+// The page and browser of the copy are cloned with rod's Context, so cancelling ctx
+// or reaching its deadline aborts navigation, element waits, humanized sleeps and scroll loops.
+// The original bot is left untouched, which makes it cheap to bind one bot per job:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+//	defer cancel()
+//
+//	err := bot.WithContext(ctx).Open(uri)
+func (b *Bot) WithContext(ctx context.Context) *Bot {
+	nb := *b
+	nb.ctx = ctx
+
+	if b.page != nil {
+		nb.page = b.page.Context(ctx)
+	}
+
+	if b.prevPage != nil {
+		nb.prevPage = b.prevPage.Context(ctx)
+	}
+
+	if b.browser != nil {
+		nb.browser = b.browser.Context(ctx)
+	}
+
+	return &nb
+}
+
+// Context returns the context bound by WithContext, or context.Background() if none.
+func (b *Bot) Context() context.Context {
+	if b.ctx == nil {
+		return context.Background()
+	}
+
+	return b.ctx
+}
+
+// bindContext binds page to the bot's context if one is set.
+func (b *Bot) bindContext(page *rod.Page) *rod.Page {
+	if b.ctx == nil || page == nil {
+		return page
+	}
+
+	return page.Context(b.ctx)
+}
+
+// randSleep is RandSleep honouring the bot's context.
+func (b *Bot) randSleep(minSec, maxSec float64) error {
+	_, err := RandSleepCtx(b.Context(), minSec, maxSec)
+	return err
+}
+
+// sleepNap sleeps 1~2s, see RandSleepNap.
+func (b *Bot) sleepNap() error {
+	return b.randSleep(1.0, 2.0) //nolint:mnd
+}
+
+// sleepPT100Ms sleeps 0.1~0.2s, see SleepPT100Ms.
+func (b *Bot) sleepPT100Ms() error {
+	return b.randSleep(0.1, 0.2) //nolint:mnd
+}
+
+// sleepPT500Ms sleeps 0.5~0.6s, see SleepPT500Ms.
+func (b *Bot) sleepPT500Ms() error {
+	return b.randSleep(0.5, 0.6) //nolint:mnd
+}
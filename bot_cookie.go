@@ -5,21 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
-	"net/http"
 	"net/url"
-	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/gookit/goutil/fsutil"
-	"github.com/jrefior/uncurl"
 )
 
 const (
 	_defaultCookieFolder = ".cookies"
-	// File permissions
-	_cookieFilePermissions fs.FileMode = 0644
+	// File permissions, cookie files hold live auth tokens, so only owner can read/write.
+	_cookieFilePermissions fs.FileMode = 0o600
 
 	// HTTP schemes
 	_schemeHTTP  = "http"
@@ -45,8 +41,9 @@ var ErrEmptyCookieStr = errors.New("no cookie str found")
 //   - Marshals these cookies into JSON format.
 //
 // 3. Writes cookies to file:
-//   - Opens or creates the cookie file with 0644 permissions (rw-r--r--).
-//   - Writes the JSON-encoded cookie data to the file.
+//   - Opens or creates the cookie file with 0600 permissions (rw-------).
+//   - Encrypts the JSON-encoded cookie data if a key is set by WithCookieKey or CookieKeyEnv.
+//   - Writes the data to the file.
 //
 // Returns:
 //   - string: The full path to the cookie file where the cookies were saved.
@@ -80,7 +77,7 @@ func (b *Bot) DumpCookies() (string, error) {
 		return "", fmt.Errorf("cannot marshal cookies: %w", err)
 	}
 
-	err = os.WriteFile(b.cookieFile, content, _cookieFilePermissions)
+	err = b.writeCookieFile(b.cookieFile, content)
 	if err != nil {
 		return "", fmt.Errorf("cannot save file: %w", err)
 	}
@@ -126,7 +123,7 @@ func (b *Bot) getRawCookies(filepath string) ([]byte, error) {
 		return nil, ErrMissingCookieFile
 	}
 
-	return os.ReadFile(filepath)
+	return b.readCookieFile(filepath)
 }
 
 // LoadCookies loads cookies from a file or from previously stored cURL data and converts them
@@ -143,11 +140,13 @@ func (b *Bot) getRawCookies(filepath string) ([]byte, error) {
 // Function behavior:
 // 1. Retrieves raw cookie data:
 //   - If copyAsCURLCookies is not empty, it uses this data.
-//   - Otherwise, it reads from the specified file or an auto-generated file based on the current URL.
+//   - Otherwise, it reads from the specified file or an auto-generated file based on the current URL,
+//     the file is decrypted if it was written with a cookie key.
 //
 // 2. Determines the format of the cookie data:
 //   - If the data is in JSON format, it parses it as proto.NetworkCookie objects.
-//   - If not JSON, it assumes cURL format and parses accordingly.
+//   - If it is a Netscape `cookies.txt`, it calls ParseNetscapeCookies.
+//   - Otherwise, it assumes cURL format and parses accordingly.
 //
 // 3. For JSON format:
 //   - Unmarshals the data into proto.NetworkCookie objects.
@@ -165,8 +164,8 @@ func (b *Bot) getRawCookies(filepath string) ([]byte, error) {
 //	// Use the cookies with the bot...
 //
 // Notes:
-//   - This function is flexible and can handle both JSON-formatted cookie files (typically saved
-//     by DumpCookies) and cURL-formatted cookie strings.
+//   - This function is flexible and can handle JSON-formatted cookie files (typically saved
+//     by DumpCookies), Netscape `cookies.txt` files and cURL-formatted cookie strings.
 //   - When loading from a file, ensure the bot has read permissions for the cookie file.
 //   - If filepath is empty and the bot can't determine the current URL, this will result in an error.
 //   - The returned cookies are in a format ready to be used with the bot's page or browser instance.
@@ -175,6 +174,7 @@ func (b *Bot) getRawCookies(filepath string) ([]byte, error) {
 // See also:
 // - DumpCookies: For saving cookies to a file.
 // - ParseCURLCookies: For the specific handling of cURL-formatted cookie strings.
+// - ParseNetscapeCookies: For the specific handling of Netscape `cookies.txt`.
 func (b *Bot) LoadCookies(filepath string) ([]*proto.NetworkCookieParam, error) {
 	raw, err := b.getRawCookies(filepath)
 	if err != nil {
@@ -182,6 +182,10 @@ func (b *Bot) LoadCookies(filepath string) ([]*proto.NetworkCookieParam, error)
 	}
 
 	if !IsJSON(string(raw)) {
+		if IsNetscapeCookies(string(raw)) {
+			return ParseNetscapeCookies(string(raw))
+		}
+
 		// non json format, means from cURL
 		// parse as cURL
 		return ParseCURLCookies(string(raw))
@@ -198,27 +202,34 @@ func (b *Bot) LoadCookies(filepath string) ([]*proto.NetworkCookieParam, error)
 	}
 
 	for _, cookie := range cookies {
-		port := &cookie.SourcePort
-		nodes = append(nodes, &proto.NetworkCookieParam{
-			Name:         cookie.Name,
-			Value:        cookie.Value,
-			URL:          b.CurrentURL(),
-			Domain:       cookie.Domain,
-			Path:         cookie.Path,
-			Secure:       cookie.Secure,
-			HTTPOnly:     cookie.HTTPOnly,
-			SameSite:     cookie.SameSite,
-			Expires:      cookie.Expires,
-			Priority:     cookie.Priority,
-			SameParty:    cookie.SameParty,
-			SourceScheme: cookie.SourceScheme,
-			SourcePort:   port,
-		})
+		nodes = append(nodes, CookieToParam(cookie, b.CurrentURL()))
 	}
 
 	return nodes, nil
 }
 
+// CookieToParam converts a cookie read from the browser to the param used to set it back,
+// uri is optional, when empty the cookie is set by its domain and path.
+func CookieToParam(cookie proto.NetworkCookie, uri string) *proto.NetworkCookieParam {
+	port := cookie.SourcePort
+
+	return &proto.NetworkCookieParam{
+		Name:         cookie.Name,
+		Value:        cookie.Value,
+		URL:          uri,
+		Domain:       cookie.Domain,
+		Path:         cookie.Path,
+		Secure:       cookie.Secure,
+		HTTPOnly:     cookie.HTTPOnly,
+		SameSite:     cookie.SameSite,
+		Expires:      cookie.Expires,
+		Priority:     cookie.Priority,
+		SameParty:    cookie.SameParty,
+		SourceScheme: cookie.SourceScheme,
+		SourcePort:   &port,
+	}
+}
+
 // createCookieFilenameFromURL generates a cookie filename based on the given URL.
 // If no URL is provided, it uses the bot's current URL.
 // Returns the full filepath for the cookie file and any error encountered.
@@ -243,7 +254,7 @@ func (b *Bot) createCookieFilenameFromURL(uri string) (string, error) {
 // parses the content, and returns NetworkCookieParam objects.
 // It returns an error if the file cannot be read or parsed.
 func (b *Bot) ParseCURLCookiesFromFile(filePath string) ([]*proto.NetworkCookieParam, error) {
-	raw, err := os.ReadFile(filePath)
+	raw, err := b.readCookieFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -251,81 +262,6 @@ func (b *Bot) ParseCURLCookiesFromFile(filePath string) ([]*proto.NetworkCookieP
 	return ParseCURLCookies(string(raw))
 }
 
-// ParseCURLCookies converts a cURL-formatted cookie string into NetworkCookieParam objects.
-//
-// It extracts cookies from the "Cookie" header in the cURL string, parses each cookie,
-// and creates NetworkCookieParam objects with appropriate attributes based on the URL.
-//
-// Parameters:
-//   - raw: string - The raw cURL-formatted cookie string.
-//
-// Returns:
-//   - []*proto.NetworkCookieParam: Slice of parsed cookie objects.
-//   - error: Error if parsing fails or no cookies are found.
-//
-// Usage:
-//
-//	cookies, err := ParseCURLCookies(curlString)
-func ParseCURLCookies(raw string) ([]*proto.NetworkCookieParam, error) {
-	curl, err := uncurl.NewString(raw)
-	if err != nil {
-		return nil, fmt.Errorf("cannot new copy as curl: %w", err)
-	}
-
-	cookieArr := getHeader(curl.Header(), "Cookie")
-	if len(cookieArr) == 0 {
-		return nil, ErrEmptyCookieStr
-	}
-
-	uriQuery, err := url.Parse(curl.Target())
-	if err != nil {
-		return nil, err
-	}
-
-	var nodes []*proto.NetworkCookieParam
-
-	for _, pair := range strings.Split(cookieArr[0], "; ") {
-		pair = strings.TrimSpace(pair)
-		if pair == "" {
-			continue
-		}
-
-		arr := strings.Split(pair, "=")
-		httpOnly := true
-		port := _portHTTP
-
-		if uriQuery.Scheme == _schemeHTTPS {
-			httpOnly = false
-			port = _portHTTPS
-		}
-
-		ckObj := &proto.NetworkCookieParam{
-			Name:       arr[0],
-			Value:      arr[1],
-			Domain:     uriQuery.Host,
-			Path:       uriQuery.Path,
-			HTTPOnly:   httpOnly,
-			SourcePort: &port,
-		}
-
-		nodes = append(nodes, ckObj)
-	}
-
-	return nodes, nil
-}
-
-// getHeader retrieves the values for a given header key from an http.Header object.
-// It checks for both the original key and its lowercase version.
-// Returns a slice of string values associated with the header key.
-func getHeader(header http.Header, key string) []string {
-	cookieStr, b := header[key]
-	if b {
-		return cookieStr
-	}
-
-	return header[strings.ToLower(key)]
-}
-
 // flattenNodes converts a slice of NetworkCookieParam objects into a slice of strings,
 // where each string is in the format "name=value".
 // Returns a slice of strings representing the flattened cookie data.
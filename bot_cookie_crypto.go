@@ -0,0 +1,181 @@
+package wee
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	crand "crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// CookieKeyEnv is the env var to read the cookie encryption key from, WithCookieKey takes precedence.
+	CookieKeyEnv = "WEE_COOKIE_KEY"
+
+	_encryptedCookiePrefix = "WEE-AESGCM-V1:"
+	// _cookieKeyLen is the key length of AES-256.
+	_cookieKeyLen = 32
+)
+
+var (
+	ErrCookieKeyRequired   = errors.New("cookie file is encrypted, but no key is set")
+	ErrInvalidCookieKey    = errors.New("cookie key must be 32 bytes, raw or base64 encoded")
+	ErrWrongCookieKey      = errors.New("cannot decrypt cookie file, wrong key or corrupted file")
+	ErrCorruptedCookieFile = errors.New("corrupted encrypted cookie file")
+)
+
+// WithCookieKey enables encryption at rest for cookie and storage state files.
+//
+// Files are encrypted with AES-256-GCM, key is 32 random bytes, raw or base64 encoded,
+// e.g. by `openssl rand -base64 32`. When not set, the key is read from CookieKeyEnv.
+// Plaintext files are still readable when a key is set, they will be encrypted on next dump.
+func WithCookieKey(key string) BotOption {
+	return func(o *Bot) {
+		o.cookieKey = []byte(key)
+	}
+}
+
+// IsEncryptedCookies reports whether raw is written by an encrypted DumpCookies or SaveStorageState.
+func IsEncryptedCookies(raw []byte) bool {
+	return bytes.HasPrefix(raw, []byte(_encryptedCookiePrefix))
+}
+
+// writeCookieFile writes content to path with owner only permissions,
+// content is encrypted if the bot has a cookie key.
+func (b *Bot) writeCookieFile(path string, content []byte) error {
+	if len(b.cookieKey) != 0 {
+		sealed, err := encryptCookies(b.cookieKey, content)
+		if err != nil {
+			return err
+		}
+
+		content = sealed
+	}
+
+	return writeSecretFile(path, content)
+}
+
+// readCookieFile reads path and decrypts it if it's encrypted.
+func (b *Bot) readCookieFile(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return decryptCookies(b.cookieKey, raw)
+}
+
+func encryptCookies(key, plain []byte) ([]byte, error) {
+	aead, err := newCookieCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := crand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("cannot generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, plain, []byte(_encryptedCookiePrefix))
+
+	return []byte(_encryptedCookiePrefix + base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+// decryptCookies returns raw as is if it's not encrypted.
+func decryptCookies(key, raw []byte) ([]byte, error) {
+	if !IsEncryptedCookies(raw) {
+		return raw, nil
+	}
+
+	if len(key) == 0 {
+		return nil, fmt.Errorf("%w: set it by WithCookieKey or env %s", ErrCookieKeyRequired, CookieKeyEnv)
+	}
+
+	encoded := bytes.TrimSpace(raw[len(_encryptedCookiePrefix):])
+
+	sealed := make([]byte, base64.StdEncoding.DecodedLen(len(encoded)))
+
+	n, err := base64.StdEncoding.Decode(sealed, encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCorruptedCookieFile, err)
+	}
+
+	sealed = sealed[:n]
+
+	aead, err := newCookieCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrCorruptedCookieFile
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(_encryptedCookiePrefix))
+	if err != nil {
+		return nil, ErrWrongCookieKey
+	}
+
+	return plain, nil
+}
+
+func newCookieCipher(key []byte) (cipher.AEAD, error) {
+	key, err := parseCookieKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// parseCookieKey returns the 32 bytes key of raw, a passphrase is refused, it's not a key.
+func parseCookieKey(raw []byte) ([]byte, error) {
+	if len(raw) == _cookieKeyLen {
+		return raw, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(raw)))
+	if err == nil && len(key) == _cookieKeyLen {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("%w: got %d bytes", ErrInvalidCookieKey, len(raw))
+}
+
+// writeSecretFile writes content with owner only permissions.
+// It's written to a temp file renamed to path, so content is never readable with the mode of an existing file.
+func writeSecretFile(path string, content []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+
+	if err := f.Chmod(_cookieFilePermissions); err != nil {
+		f.Close()
+		return err
+	}
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
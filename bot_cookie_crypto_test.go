@@ -0,0 +1,89 @@
+package wee
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/coghost/wee/fixtures"
+	"github.com/stretchr/testify/suite"
+)
+
+type CookieCryptoSuite struct {
+	suite.Suite
+}
+
+func TestCookieCrypto(t *testing.T) {
+	suite.Run(t, new(CookieCryptoSuite))
+}
+
+// _testCookieKey is 32 bytes base64 encoded.
+const _testCookieKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+func (s *CookieCryptoSuite) TestRoundTrip() {
+	plain := []byte(`[{"name":"sid","value":"s001"}]`)
+
+	sealed, err := encryptCookies([]byte(_testCookieKey), plain)
+	s.Require().NoError(err)
+	s.True(IsEncryptedCookies(sealed))
+	s.NotContains(string(sealed), "s001")
+
+	got, err := decryptCookies([]byte(_testCookieKey), sealed)
+	s.Require().NoError(err)
+	s.Equal(plain, got)
+
+	_, err = decryptCookies([]byte("0123456789abcdef0123456789abcdeF"), sealed)
+	s.ErrorIs(err, ErrWrongCookieKey)
+
+	_, err = decryptCookies([]byte("secret"), sealed)
+	s.ErrorIs(err, ErrInvalidCookieKey, "a passphrase is not a key")
+
+	_, err = decryptCookies(nil, sealed)
+	s.ErrorIs(err, ErrCookieKeyRequired)
+
+	_, err = decryptCookies([]byte(_testCookieKey), []byte(_encryptedCookiePrefix+"!!"))
+	s.ErrorIs(err, ErrCorruptedCookieFile)
+}
+
+func (s *CookieCryptoSuite) TestPlaintextPassThrough() {
+	plain := []byte(`[{"name":"sid","value":"s001"}]`)
+
+	got, err := decryptCookies([]byte(_testCookieKey), plain)
+	s.Require().NoError(err)
+	s.Equal(plain, got)
+}
+
+func (s *CookieCryptoSuite) TestCookieFile() {
+	file := filepath.Join(s.T().TempDir(), "cookies.json")
+	s.Require().NoError(os.WriteFile(file, []byte("old"), 0o644))
+
+	bot := &Bot{cookieKey: []byte(_testCookieKey)}
+	s.Require().NoError(bot.writeCookieFile(file, []byte("content")))
+
+	info, err := os.Stat(file)
+	s.Require().NoError(err)
+	s.Equal(_cookieFilePermissions, info.Mode().Perm())
+
+	raw, err := os.ReadFile(file)
+	s.Require().NoError(err)
+	s.True(IsEncryptedCookies(raw))
+
+	got, err := bot.readCookieFile(file)
+	s.Require().NoError(err)
+	s.Equal("content", string(got))
+}
+
+func (s *CookieCryptoSuite) TestOpenWithWrongKey() {
+	ts := fixtures.NewTestServer()
+	defer ts.Close()
+
+	file := filepath.Join(s.T().TempDir(), "cookies.json")
+
+	writer := &Bot{cookieKey: []byte(_testCookieKey)}
+	s.Require().NoError(writer.writeCookieFile(file, []byte(`[{"name":"sid","value":"s001"}]`)))
+
+	bot := NewBotHeadless(WithCookieFile(file), WithCookieKey("0123456789abcdef0123456789abcdeF"))
+	defer bot.Cleanup()
+
+	s.ErrorIs(bot.OpenAndSetCookies(ts.URL+"/hellowee"), ErrWrongCookieKey, "not opened without the session")
+}
@@ -0,0 +1,378 @@
+package wee
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+var (
+	ErrInvalidCURL       = errors.New("invalid curl command")
+	ErrInvalidCURLCookie = errors.New("invalid curl cookie")
+)
+
+// _curlValueFlags are curl flags followed by a value, so the value is not taken as the URL.
+// -H/--header, -b/--cookie and --url are handled by parseCURLArgs.
+var _curlValueFlags = map[string]bool{
+	"-X": true, "--request": true, "--request-target": true, "--url-query": true,
+	"-d": true, "--data": true, "--data-raw": true, "--data-binary": true, "--data-urlencode": true, "--data-ascii": true,
+	"--json": true, "-F": true, "--form": true, "--form-string": true, "-T": true, "--upload-file": true,
+	"-A": true, "--user-agent": true,
+	"-e": true, "--referer": true,
+	"-u": true, "--user": true, "--oauth2-bearer": true, "--aws-sigv4": true, "--login-options": true,
+	"-x": true, "--proxy": true, "-U": true, "--proxy-user": true, "--proxy-header": true, "--preproxy": true,
+	"--noproxy": true, "--socks4": true, "--socks4a": true, "--socks5": true, "--socks5-hostname": true,
+	"--connect-to": true, "--resolve": true, "--dns-servers": true, "--doh-url": true,
+	"--interface": true, "--local-port": true, "--unix-socket": true, "--abstract-unix-socket": true,
+	"-o": true, "--output": true, "--output-dir": true, "-D": true, "--dump-header": true, "-w": true, "--write-out": true,
+	"--stderr": true, "--trace": true, "--trace-ascii": true,
+	"-c": true, "--cookie-jar": true, "-K": true, "--config": true, "--netrc-file": true,
+	"-E": true, "--cert": true, "--cert-type": true, "--key": true, "--key-type": true, "--pass": true,
+	"--cacert": true, "--capath": true, "--crlfile": true, "--pinnedpubkey": true, "--ciphers": true, "--tls-max": true,
+	"-m": true, "--max-time": true, "--connect-timeout": true, "--expect100-timeout": true,
+	"--retry": true, "--retry-delay": true, "--retry-max-time": true, "--max-redirs": true, "--max-filesize": true,
+	"-r": true, "--range": true, "-z": true, "--time-cond": true, "-Y": true, "--speed-limit": true,
+	"-y": true, "--speed-time": true, "--limit-rate": true, "--hsts": true, "--alt-svc": true,
+	"--proto": true, "--proto-redir": true, "--proto-default": true, "--variable": true,
+}
+
+// ParseCURLCookies converts a cURL command (e.g. devtools `Copy as cURL`) into NetworkCookieParam objects.
+//
+// Cookies are read from the "Cookie" header (-H/--header) and the -b/--cookie flags,
+// all of them are merged in order. Each pair is split at the first `=`,
+// so values like base64 tokens or JWTs are kept as is, and a double quoted value is unquoted.
+//
+// The cookies are scoped to the host of the URL with path `/`, and Secure is set for https.
+//
+// Returns:
+//   - ErrEmptyCookieStr if no cookie is found.
+//   - ErrInvalidCURL if the command cannot be tokenized or has no URL.
+//   - ErrInvalidCURLCookie if a pair has no name, or -b refers to a cookie file.
+//
+// Usage:
+//
+//	cookies, err := ParseCURLCookies(curlString)
+func ParseCURLCookies(raw string) ([]*proto.NetworkCookieParam, error) {
+	args, err := splitCURLArgs(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCURL, err)
+	}
+
+	target, cookieStrs, err := parseCURLArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cookieStrs) == 0 {
+		return nil, ErrEmptyCookieStr
+	}
+
+	uri, err := curlTargetURL(target)
+	if err != nil {
+		return nil, err
+	}
+
+	port := _portHTTP
+	if uri.Scheme == _schemeHTTPS {
+		port = _portHTTPS
+	}
+
+	if p := uri.Port(); p != "" {
+		if port, err = strconv.Atoi(p); err != nil {
+			return nil, fmt.Errorf("%w: bad port %q", ErrInvalidCURL, p)
+		}
+	}
+
+	var nodes []*proto.NetworkCookieParam
+
+	for _, str := range cookieStrs {
+		pairs, err := parseCookiePairs(str)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pair := range pairs {
+			sourcePort := port
+			nodes = append(nodes, &proto.NetworkCookieParam{
+				Name:       pair[0],
+				Value:      pair[1],
+				Domain:     uri.Hostname(),
+				Path:       "/",
+				Secure:     uri.Scheme == _schemeHTTPS,
+				SourcePort: &sourcePort,
+			})
+		}
+	}
+
+	if len(nodes) == 0 {
+		return nil, ErrEmptyCookieStr
+	}
+
+	return nodes, nil
+}
+
+// parseCURLArgs returns the URL and the raw cookie strings of a tokenized curl command.
+func parseCURLArgs(args []string) (string, []string, error) {
+	var (
+		target     string
+		urlFlag    string
+		cookieStrs []string
+	)
+
+	// next returns the value of flag, either attached (`-bk=v`, `--cookie=k=v`) or the following arg.
+	next := func(i *int, flag, attached string) (string, error) {
+		if attached != "" {
+			return attached, nil
+		}
+
+		*i++
+		if *i >= len(args) {
+			return "", fmt.Errorf("%w: %s requires a value", ErrInvalidCURL, flag)
+		}
+
+		return args[*i], nil
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		flag, attached := splitCURLFlag(arg)
+
+		switch {
+		case i == 0 && arg == "curl":
+		case flag == "-H" || flag == "--header":
+			header, err := next(&i, flag, attached)
+			if err != nil {
+				return "", nil, err
+			}
+
+			name, value, ok := strings.Cut(header, ":")
+			if ok && strings.EqualFold(strings.TrimSpace(name), "cookie") {
+				cookieStrs = append(cookieStrs, value)
+			}
+		case flag == "-b" || flag == "--cookie":
+			value, err := next(&i, flag, attached)
+			if err != nil {
+				return "", nil, err
+			}
+
+			if !strings.Contains(value, "=") {
+				return "", nil, fmt.Errorf("%w: cookie file %q is not supported, load it with LoadCookies", ErrInvalidCURLCookie, value)
+			}
+
+			cookieStrs = append(cookieStrs, value)
+		case flag == "--url":
+			value, err := next(&i, flag, attached)
+			if err != nil {
+				return "", nil, err
+			}
+
+			urlFlag = StrAorB(urlFlag, value)
+		case _curlValueFlags[flag]:
+			if _, err := next(&i, flag, attached); err != nil {
+				return "", nil, err
+			}
+		case strings.HasPrefix(arg, "-"):
+		default:
+			target = StrAorB(target, arg)
+		}
+	}
+
+	// --url wins over a bare argument, which may be the value of a flag missing in _curlValueFlags.
+	return StrAorB(urlFlag, target), cookieStrs, nil
+}
+
+// splitCURLFlag splits `--cookie=k=v` into (`--cookie`, `k=v`) and `-bk=v` into (`-b`, `k=v`).
+func splitCURLFlag(arg string) (string, string) {
+	if strings.HasPrefix(arg, "--") {
+		flag, value, _ := strings.Cut(arg, "=")
+		return flag, value
+	}
+
+	if strings.HasPrefix(arg, "-") && len(arg) > 2 {
+		return arg[:2], arg[2:]
+	}
+
+	return arg, ""
+}
+
+func curlTargetURL(target string) (*url.URL, error) {
+	if target == "" {
+		return nil, fmt.Errorf("%w: missing url", ErrInvalidCURL)
+	}
+
+	// curl defaults to http when scheme is omitted.
+	if !strings.Contains(target, "://") {
+		target = _schemeHTTP + "://" + target
+	}
+
+	uri, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCURL, err)
+	}
+
+	if uri.Hostname() == "" {
+		return nil, fmt.Errorf("%w: missing host in %q", ErrInvalidCURL, target)
+	}
+
+	return uri, nil
+}
+
+// parseCookiePairs parses a `Cookie` header value like `a=1; b="x y"; token=abc==`.
+func parseCookiePairs(str string) ([][2]string, error) {
+	var pairs [][2]string
+
+	for _, pair := range strings.Split(str, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidCURLCookie, pair)
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = value[1 : len(value)-1]
+		}
+
+		pairs = append(pairs, [2]string{name, value})
+	}
+
+	return pairs, nil
+}
+
+// splitCURLArgs tokenizes a shell command like bash does for `Copy as cURL (bash)`:
+// single quotes, double quotes with backslash escapes, ANSI-C `$'...'` strings
+// and backslash line continuations.
+func splitCURLArgs(raw string) ([]string, error) {
+	var (
+		args    []string
+		cur     strings.Builder
+		inToken bool
+	)
+
+	runes := []rune(strings.TrimSpace(raw))
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			if runes[i] == '\n' || runes[i] == '\r' {
+				// line continuation
+				if runes[i] == '\r' && i+1 < len(runes) && runes[i+1] == '\n' {
+					i++
+				}
+
+				continue
+			}
+
+			cur.WriteRune(runes[i])
+			inToken = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inToken {
+				args = append(args, cur.String())
+				cur.Reset()
+
+				inToken = false
+			}
+		case r == '\'':
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+
+			cur.WriteString(string(runes[i+1 : end]))
+			i = end
+			inToken = true
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '\'':
+			end, err := readANSICString(runes, i+2, &cur)
+			if err != nil {
+				return nil, err
+			}
+
+			i = end
+			inToken = true
+		case r == '"':
+			end, err := readDoubleQuoted(runes, i+1, &cur)
+			if err != nil {
+				return nil, err
+			}
+
+			i = end
+			inToken = true
+		default:
+			cur.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if inToken {
+		args = append(args, cur.String())
+	}
+
+	return args, nil
+}
+
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// readDoubleQuoted writes the content of a "..." string starting at from, and returns the index of closing quote.
+func readDoubleQuoted(runes []rune, from int, cur *strings.Builder) (int, error) {
+	for i := from; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '"':
+			return i, nil
+		case r == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[i+1]):
+			i++
+			if runes[i] != '\n' {
+				cur.WriteRune(runes[i])
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+
+	return 0, errors.New("unterminated double quote")
+}
+
+// readANSICString writes the content of a $'...' string starting at from, and returns the index of closing quote.
+func readANSICString(runes []rune, from int, cur *strings.Builder) (int, error) {
+	escapes := map[rune]rune{'n': '\n', 't': '\t', 'r': '\r', '\\': '\\', '\'': '\'', '"': '"'}
+
+	for i := from; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\'' {
+			return i, nil
+		}
+
+		if r == '\\' && i+1 < len(runes) {
+			if esc, ok := escapes[runes[i+1]]; ok {
+				cur.WriteRune(esc)
+				i++
+
+				continue
+			}
+		}
+
+		cur.WriteRune(r)
+	}
+
+	return 0, errors.New("unterminated $' quote")
+}
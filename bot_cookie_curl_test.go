@@ -0,0 +1,101 @@
+package wee
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/suite"
+)
+
+type CURLCookieSuite struct {
+	suite.Suite
+}
+
+func TestCURLCookie(t *testing.T) {
+	suite.Run(t, new(CURLCookieSuite))
+}
+
+func (s *CURLCookieSuite) TestParse() {
+	raw := `curl 'https://www.example.com:8443/app/list?page=1' \
+  -H 'accept: text/html' \
+  -H 'cookie: sid=s001; token=YWJj==; jwt=a.b.c; q="quoted value"' \
+  --compressed`
+
+	nodes, err := ParseCURLCookies(raw)
+	s.Require().NoError(err)
+	s.Equal([]string{"sid=s001", "token=YWJj==", "jwt=a.b.c", "q=quoted value"}, flattenNodes(nodes))
+
+	port := 8443
+	s.Equal(&proto.NetworkCookieParam{
+		Name:       "sid",
+		Value:      "s001",
+		Domain:     "www.example.com",
+		Path:       "/",
+		Secure:     true,
+		SourcePort: &port,
+	}, nodes[0])
+}
+
+func (s *CURLCookieSuite) TestCookieFlags() {
+	tests := []struct {
+		name string
+		raw  string
+		want []string
+	}{
+		{
+			name: "-b",
+			raw:  `curl -b 'a=1; b=2' http://example.com`,
+			want: []string{"a=1", "b=2"},
+		},
+		{
+			name: "--cookie= and header merged",
+			raw:  `curl --cookie=a=1 -H "Cookie: b=\"2\"" --url http://example.com`,
+			want: []string{"a=1", "b=2"},
+		},
+		{
+			name: "attached -b and value flags",
+			raw:  `curl -X POST -e http://referer.com -ba=1 example.com`,
+			want: []string{"a=1"},
+		},
+		{
+			name: "connect-to, resolve and --url",
+			raw:  `curl --connect-to example.com:80:127.0.0.1:8080 --resolve example.com:80:127.0.0.1 -b a=1 --url http://example.com`,
+			want: []string{"a=1"},
+		},
+		{
+			name: "ansi-c quoting",
+			raw:  `curl $'http://example.com' -H $'Cookie: a=it\'s'`,
+			want: []string{"a=it's"},
+		},
+	}
+
+	for _, tt := range tests {
+		nodes, err := ParseCURLCookies(tt.raw)
+		s.Require().NoError(err, tt.name)
+		s.Equal(tt.want, flattenNodes(nodes), tt.name)
+		s.Equal("example.com", nodes[0].Domain, tt.name)
+		s.False(nodes[0].Secure, tt.name)
+	}
+}
+
+func (s *CURLCookieSuite) TestErrors() {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr error
+	}{
+		{"no cookie", `curl 'http://example.com' -H 'accept: */*'`, ErrEmptyCookieStr},
+		{"empty cookie", `curl 'http://example.com' -H 'Cookie: ; '`, ErrEmptyCookieStr},
+		{"pair without =", `curl 'http://example.com' -H 'Cookie: a=1; broken'`, ErrInvalidCURLCookie},
+		{"empty name", `curl 'http://example.com' -H 'Cookie: =1'`, ErrInvalidCURLCookie},
+		{"cookie file", `curl -b cookies.txt 'http://example.com'`, ErrInvalidCURLCookie},
+		{"missing url", `curl -H 'Cookie: a=1'`, ErrInvalidCURL},
+		{"unterminated quote", `curl 'http://example.com' -H 'Cookie: a=1`, ErrInvalidCURL},
+		{"missing flag value", `curl http://example.com -b`, ErrInvalidCURL},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseCURLCookies(tt.raw)
+		s.ErrorIs(err, tt.wantErr, tt.name)
+	}
+}
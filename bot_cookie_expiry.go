@@ -0,0 +1,230 @@
+package wee
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+)
+
+// DefaultCookieExpiringSoon is the window used by InspectCookies to report a cookie as expiring soon.
+const DefaultCookieExpiringSoon = 24 * time.Hour
+
+var (
+	ErrRequiredCookieMissing = errors.New("required cookie missing")
+	ErrRequiredCookieExpired = errors.New("required cookie expired")
+)
+
+type CookieStatus string
+
+const (
+	CookieValid        CookieStatus = "valid"
+	CookieExpiringSoon CookieStatus = "expiring_soon"
+	CookieExpired      CookieStatus = "expired"
+	CookieSession      CookieStatus = "session"
+)
+
+// CookieCheckHook is called by OpenAndSetCookies when required cookies are missing or expired,
+// err wraps ErrRequiredCookieMissing and/or ErrRequiredCookieExpired.
+//
+// Returning nil keeps going as a warning, a non-nil error is returned by OpenAndSetCookies.
+type CookieCheckHook func(err error) error
+
+// DomainCookieReport holds the cookie names of one domain grouped by CookieStatus.
+type DomainCookieReport struct {
+	Domain       string
+	Valid        []string
+	ExpiringSoon []string
+	Expired      []string
+	Session      []string
+}
+
+// WithPruneExpiredCookies drops expired cookies in OpenAndSetCookies before setting them to browser.
+func WithPruneExpiredCookies(b bool) BotOption {
+	return func(o *Bot) {
+		o.pruneExpiredCookies = b
+	}
+}
+
+// WithRequiredCookies checks cookies loaded by OpenAndSetCookies contain all names and none is expired,
+// hook is called when the check fails, a nil hook logs a warning.
+func WithRequiredCookies(hook CookieCheckHook, names ...string) BotOption {
+	return func(o *Bot) {
+		o.requiredCookies = append(o.requiredCookies, names...)
+		o.cookieCheckHook = hook
+	}
+}
+
+// CookieStatusAt returns the status of node at now,
+// soon is the window before expiry a cookie is reported as CookieExpiringSoon.
+func CookieStatusAt(node *proto.NetworkCookieParam, soon time.Duration, now time.Time) CookieStatus {
+	// session cookies have no expiry, which is -1 from devtools or 0 from other formats.
+	if node.Expires <= 0 {
+		return CookieSession
+	}
+
+	expires := node.Expires.Time()
+
+	switch {
+	case !expires.After(now):
+		return CookieExpired
+	case expires.Before(now.Add(soon)):
+		return CookieExpiringSoon
+	default:
+		return CookieValid
+	}
+}
+
+// InspectCookies groups nodes by domain and CookieStatus, the result is sorted by domain.
+func InspectCookies(nodes []*proto.NetworkCookieParam, soon time.Duration, now time.Time) []*DomainCookieReport {
+	byDomain := make(map[string]*DomainCookieReport)
+
+	for _, node := range nodes {
+		domain := cookieParamDomain(node)
+
+		report, ok := byDomain[domain]
+		if !ok {
+			report = &DomainCookieReport{Domain: domain}
+			byDomain[domain] = report
+		}
+
+		switch CookieStatusAt(node, soon, now) {
+		case CookieValid:
+			report.Valid = append(report.Valid, node.Name)
+		case CookieExpiringSoon:
+			report.ExpiringSoon = append(report.ExpiringSoon, node.Name)
+		case CookieExpired:
+			report.Expired = append(report.Expired, node.Name)
+		case CookieSession:
+			report.Session = append(report.Session, node.Name)
+		}
+	}
+
+	reports := make([]*DomainCookieReport, 0, len(byDomain))
+	for _, report := range byDomain {
+		reports = append(reports, report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Domain < reports[j].Domain
+	})
+
+	return reports
+}
+
+// PruneExpiredCookies returns nodes not expired at now, and names of the pruned ones.
+// Session cookies are always kept.
+func PruneExpiredCookies(nodes []*proto.NetworkCookieParam, now time.Time) ([]*proto.NetworkCookieParam, []string) {
+	var (
+		kept   []*proto.NetworkCookieParam
+		pruned []string
+	)
+
+	for _, node := range nodes {
+		if CookieStatusAt(node, 0, now) == CookieExpired {
+			pruned = append(pruned, node.Name)
+			continue
+		}
+
+		kept = append(kept, node)
+	}
+
+	return kept, pruned
+}
+
+// CheckRequiredCookies returns an error wrapping ErrRequiredCookieMissing and/or ErrRequiredCookieExpired
+// if any of names is not in nodes or all cookies with that name are expired at now.
+func CheckRequiredCookies(nodes []*proto.NetworkCookieParam, now time.Time, names ...string) error {
+	var missing, expired []string
+
+	for _, name := range names {
+		found, alive := false, false
+
+		for _, node := range nodes {
+			if node.Name != name {
+				continue
+			}
+
+			found = true
+			alive = alive || CookieStatusAt(node, 0, now) != CookieExpired
+		}
+
+		switch {
+		case !found:
+			missing = append(missing, name)
+		case !alive:
+			expired = append(expired, name)
+		}
+	}
+
+	var errs []error
+	if len(missing) != 0 {
+		errs = append(errs, fmt.Errorf("%w: %v", ErrRequiredCookieMissing, missing))
+	}
+
+	if len(expired) != 0 {
+		errs = append(errs, fmt.Errorf("%w: %v", ErrRequiredCookieExpired, expired))
+	}
+
+	return errors.Join(errs...)
+}
+
+// InspectCookies loads cookies by LoadCookies and reports them per domain,
+// cookies expiring within DefaultCookieExpiringSoon are reported as ExpiringSoon.
+func (b *Bot) InspectCookies(filepath string) ([]*DomainCookieReport, error) {
+	nodes, err := b.LoadCookies(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	return InspectCookies(nodes, DefaultCookieExpiringSoon, time.Now()), nil
+}
+
+// checkLoadedCookies prunes expired cookies and runs required cookie check,
+// both are enabled by bot options.
+func (b *Bot) checkLoadedCookies(nodes []*proto.NetworkCookieParam) ([]*proto.NetworkCookieParam, error) {
+	now := time.Now()
+
+	if b.pruneExpiredCookies {
+		var pruned []string
+
+		nodes, pruned = PruneExpiredCookies(nodes, now)
+		if len(pruned) != 0 {
+			b.logger.Info("pruned expired cookies", zap.Strings("names", pruned))
+		}
+	}
+
+	if len(b.requiredCookies) == 0 {
+		return nodes, nil
+	}
+
+	err := CheckRequiredCookies(nodes, now, b.requiredCookies...)
+	if err == nil {
+		return nodes, nil
+	}
+
+	if b.cookieCheckHook == nil {
+		b.logger.Warn("required cookies check failed", zap.String("cookie", b.cookieFile), zap.Error(err))
+		return nodes, nil
+	}
+
+	return nodes, b.cookieCheckHook(err)
+}
+
+// cookieParamDomain is the domain of node, or the host of its url for a host-only cookie.
+func cookieParamDomain(node *proto.NetworkCookieParam) string {
+	if node.Domain != "" || node.URL == "" {
+		return node.Domain
+	}
+
+	uri, err := url.Parse(node.URL)
+	if err != nil {
+		return node.Domain
+	}
+
+	return uri.Hostname()
+}
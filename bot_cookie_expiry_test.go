@@ -0,0 +1,84 @@
+package wee
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type CookieExpirySuite struct {
+	suite.Suite
+	now   time.Time
+	nodes []*proto.NetworkCookieParam
+}
+
+func TestCookieExpiry(t *testing.T) {
+	suite.Run(t, new(CookieExpirySuite))
+}
+
+func (s *CookieExpirySuite) SetupTest() {
+	// checkLoadedCookies uses time.Now.
+	s.now = time.Now().Truncate(time.Second)
+	at := func(d time.Duration) proto.TimeSinceEpoch {
+		return proto.TimeSinceEpoch(s.now.Add(d).Unix())
+	}
+
+	s.nodes = []*proto.NetworkCookieParam{
+		{Name: "sid", Domain: "b.com", Expires: at(-time.Hour)},
+		{Name: "token", Domain: "a.com", Expires: at(30 * 24 * time.Hour)},
+		{Name: "csrf", Domain: "a.com", Expires: at(time.Hour)},
+		{Name: "tz", Domain: "a.com", Expires: -1},
+		{Name: "lang", URL: "https://b.com/"},
+	}
+}
+
+func (s *CookieExpirySuite) TestInspect() {
+	reports := InspectCookies(s.nodes, DefaultCookieExpiringSoon, s.now)
+	s.Equal([]*DomainCookieReport{
+		{Domain: "a.com", Valid: []string{"token"}, ExpiringSoon: []string{"csrf"}, Session: []string{"tz"}},
+		{Domain: "b.com", Expired: []string{"sid"}, Session: []string{"lang"}},
+	}, reports)
+}
+
+func (s *CookieExpirySuite) TestPrune() {
+	kept, pruned := PruneExpiredCookies(s.nodes, s.now)
+	s.Equal([]string{"sid"}, pruned)
+	s.Equal([]string{"token=", "csrf=", "tz=", "lang="}, flattenNodes(kept))
+}
+
+func (s *CookieExpirySuite) TestCheckRequired() {
+	s.NoError(CheckRequiredCookies(s.nodes, s.now, "token", "tz"))
+
+	err := CheckRequiredCookies(s.nodes, s.now, "token", "sid", "uid")
+	s.ErrorIs(err, ErrRequiredCookieMissing)
+	s.ErrorIs(err, ErrRequiredCookieExpired)
+	s.ErrorContains(err, "[uid]")
+	s.ErrorContains(err, "[sid]")
+}
+
+func (s *CookieExpirySuite) TestCheckLoadedCookies() {
+	errHook := errors.New("login expired")
+
+	var hooked error
+
+	bot := &Bot{logger: zap.NewNop()}
+	WithPruneExpiredCookies(true)(bot)
+	WithRequiredCookies(func(err error) error {
+		hooked = err
+		return errHook
+	}, "sid")(bot)
+
+	nodes, err := bot.checkLoadedCookies(s.nodes)
+	s.ErrorIs(err, errHook)
+	s.ErrorIs(hooked, ErrRequiredCookieMissing, "sid is pruned before check")
+	s.Len(nodes, 4)
+
+	// without hook, it's only a warning.
+	bot.cookieCheckHook = nil
+	_, err = bot.checkLoadedCookies(s.nodes)
+	s.NoError(err)
+}
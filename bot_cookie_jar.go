@@ -0,0 +1,188 @@
+package wee
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+var _sameSiteToHTTP = map[proto.NetworkCookieSameSite]http.SameSite{
+	proto.NetworkCookieSameSiteStrict: http.SameSiteStrictMode,
+	proto.NetworkCookieSameSiteLax:    http.SameSiteLaxMode,
+	proto.NetworkCookieSameSiteNone:   http.SameSiteNoneMode,
+}
+
+// NewCookieJar creates a net/http cookie jar filled with all cookies of the browser,
+// so requests can go on with a plain http.Client after logging in with the bot:
+//
+//	jar, err := bot.NewCookieJar()
+//	client := &http.Client{Jar: jar}
+func (b *Bot) NewCookieJar() (*cookiejar.Jar, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.ExportCookiesToJar(jar); err != nil {
+		return nil, err
+	}
+
+	return jar, nil
+}
+
+// ExportCookiesToJar adds all cookies of the browser into jar.
+func (b *Bot) ExportCookiesToJar(jar http.CookieJar) error {
+	cookies, err := b.browser.GetCookies()
+	if err != nil {
+		return fmt.Errorf("cannot get cookies: %w", err)
+	}
+
+	CookiesToJar(jar, cookies)
+
+	return nil
+}
+
+// ImportCookiesFromJar sets cookies jar holds for urls to the browser.
+//
+// http.CookieJar only exposes name and value, so the cookies are set as host-only session cookies of each url,
+// with path `/`. Nothing is set if jar has no cookie for urls.
+func (b *Bot) ImportCookiesFromJar(jar http.CookieJar, urls ...string) error {
+	nodes, err := CookiesFromJar(jar, urls...)
+	if err != nil {
+		return err
+	}
+
+	// SetCookies(nil) clears all cookies of the browser.
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	if err := b.browser.SetCookies(nodes); err != nil {
+		return fmt.Errorf("cannot set cookies: %w", err)
+	}
+
+	return nil
+}
+
+// CookiesToJar adds cookies into jar, each cookie is added to the url built from its domain, path and secure flag.
+func CookiesToJar(jar http.CookieJar, cookies []*proto.NetworkCookie) {
+	for _, c := range cookies {
+		scheme := _schemeHTTP
+		if c.Secure {
+			scheme = _schemeHTTPS
+		}
+
+		uri := &url.URL{
+			Scheme: scheme,
+			Host:   strings.TrimPrefix(c.Domain, "."),
+			Path:   StrAorB(c.Path, "/"),
+		}
+
+		jar.SetCookies(uri, []*http.Cookie{NetworkCookieToHTTP(c)})
+	}
+}
+
+// CookiesFromJar returns cookies jar holds for urls as NetworkCookieParam objects,
+// a cookie returned for several urls of the same host is returned once.
+func CookiesFromJar(jar http.CookieJar, urls ...string) ([]*proto.NetworkCookieParam, error) {
+	var nodes []*proto.NetworkCookieParam
+
+	seen := make(map[string]bool)
+
+	for _, raw := range urls {
+		uri, err := url.Parse(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range jar.Cookies(uri) {
+			node := HTTPCookieToParam(c, uri)
+
+			key := strings.Join([]string{node.Name, StrAorB(node.Domain, uri.Host), node.Path}, "\n")
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes, nil
+}
+
+// NetworkCookieToHTTP converts a browser cookie to http.Cookie,
+// cookies whose domain has no leading dot are host-only, so Domain is left empty.
+func NetworkCookieToHTTP(c *proto.NetworkCookie) *http.Cookie {
+	hc := &http.Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Path:     c.Path,
+		Secure:   c.Secure,
+		HttpOnly: c.HTTPOnly,
+		SameSite: _sameSiteToHTTP[c.SameSite],
+	}
+
+	if strings.HasPrefix(c.Domain, ".") {
+		hc.Domain = c.Domain
+	}
+
+	if !c.Session && c.Expires > 0 {
+		hc.Expires = c.Expires.Time()
+	}
+
+	return hc
+}
+
+// HTTPCookieToParam converts http.Cookie to NetworkCookieParam by CookieToParam,
+// cookie without Domain is host-only, it's set by the url of uri's host, cookie without Path has path `/`.
+func HTTPCookieToParam(c *http.Cookie, uri *url.URL) *proto.NetworkCookieParam {
+	cookie := proto.NetworkCookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Domain:   c.Domain,
+		Path:     StrAorB(c.Path, "/"),
+		Secure:   c.Secure,
+		HTTPOnly: c.HttpOnly,
+	}
+
+	cookie.SourcePort = _portHTTP
+	if uri.Scheme == _schemeHTTPS {
+		cookie.SourcePort = _portHTTPS
+	}
+
+	if port, err := strconv.Atoi(uri.Port()); err == nil {
+		cookie.SourcePort = port
+	}
+
+	for k, v := range _sameSiteToHTTP {
+		if v == c.SameSite {
+			cookie.SameSite = k
+		}
+	}
+
+	switch {
+	case c.MaxAge > 0:
+		cookie.Expires = proto.TimeSinceEpoch(time.Now().Add(time.Duration(c.MaxAge) * time.Second).Unix())
+	case !c.Expires.IsZero():
+		cookie.Expires = proto.TimeSinceEpoch(c.Expires.Unix())
+	}
+
+	cookie.Session = cookie.Expires == 0
+
+	// chrome makes a cookie set by url without domain host-only.
+	if cookie.Domain != "" {
+		return CookieToParam(cookie, "")
+	}
+
+	hostURL := &url.URL{Scheme: uri.Scheme, Host: uri.Host, Path: cookie.Path}
+
+	return CookieToParam(cookie, hostURL.String())
+}
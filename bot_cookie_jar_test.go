@@ -0,0 +1,81 @@
+package wee
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/suite"
+)
+
+type CookieJarSuite struct {
+	suite.Suite
+}
+
+func TestCookieJar(t *testing.T) {
+	suite.Run(t, new(CookieJarSuite))
+}
+
+func (s *CookieJarSuite) TestCookiesToJar() {
+	expires := proto.TimeSinceEpoch(time.Now().Add(time.Hour).Unix())
+	cookies := []*proto.NetworkCookie{
+		{Name: "sid", Value: "a=b==", Domain: ".example.com", Path: "/", Expires: expires},
+		{Name: "host", Value: "1", Domain: "www.example.com", Path: "/", Session: true, Expires: -1},
+		{Name: "app", Value: "2", Domain: "www.example.com", Path: "/app", Session: true, Expires: -1},
+		{Name: "sec", Value: "3", Domain: "www.example.com", Path: "/", Secure: true, Session: true, Expires: -1},
+	}
+
+	jar, err := cookiejar.New(nil)
+	s.Require().NoError(err)
+
+	CookiesToJar(jar, cookies)
+
+	get := func(raw string) []string {
+		uri, _ := url.Parse(raw)
+
+		var got []string
+		for _, c := range jar.Cookies(uri) {
+			got = append(got, c.Name+"="+c.Value)
+		}
+
+		return got
+	}
+
+	s.ElementsMatch([]string{"sid=a=b==", "host=1"}, get("http://www.example.com/"))
+	s.ElementsMatch([]string{"sid=a=b==", "host=1", "app=2", "sec=3"}, get("https://www.example.com/app/list"))
+	s.ElementsMatch([]string{"sid=a=b=="}, get("http://api.example.com/"))
+}
+
+func (s *CookieJarSuite) TestCookiesFromJar() {
+	jar, err := cookiejar.New(nil)
+	s.Require().NoError(err)
+
+	uri, _ := url.Parse("https://api.example.com/v1/login")
+	jar.SetCookies(uri, []*http.Cookie{{Name: "token", Value: "jwt.a.b", Path: "/"}})
+
+	nodes, err := CookiesFromJar(jar, uri.String(), "https://api.example.com/v1/me", "https://other.com")
+	s.Require().NoError(err)
+	s.Require().Len(nodes, 1, "same cookie of the same host")
+
+	port := 443
+	s.Equal(&proto.NetworkCookieParam{
+		Name:       "token",
+		Value:      "jwt.a.b",
+		URL:        "https://api.example.com/",
+		Path:       "/",
+		SourcePort: &port,
+	}, nodes[0], "host-only, and not secure as it's not set secure")
+}
+
+func (s *CookieJarSuite) TestHTTPCookieExpiry() {
+	uri, _ := url.Parse("http://example.com")
+
+	node := HTTPCookieToParam(&http.Cookie{Name: "a", Value: "1", MaxAge: 3600}, uri)
+	s.Equal(CookieValid, CookieStatusAt(node, time.Minute, time.Now()))
+
+	node = HTTPCookieToParam(&http.Cookie{Name: "a", Value: "1"}, uri)
+	s.Equal(CookieSession, CookieStatusAt(node, time.Minute, time.Now()))
+}
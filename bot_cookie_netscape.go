@@ -0,0 +1,247 @@
+package wee
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/gookit/goutil/fsutil"
+)
+
+const (
+	_netscapeHeader       = "# Netscape HTTP Cookie File"
+	_netscapeHeaderShort  = "# HTTP Cookie File"
+	_netscapeHTTPOnly     = "#HttpOnly_"
+	_netscapeTrue         = "TRUE"
+	_netscapeFalse        = "FALSE"
+	_netscapeFieldsLen    = 7
+	_netscapeSessionStamp = 0
+)
+
+var ErrInvalidNetscapeCookie = errors.New("invalid netscape cookie line")
+
+// IsNetscapeCookies reports whether raw looks like a Netscape `cookies.txt`,
+// as used by curl, wget and yt-dlp.
+//
+// It is true when raw starts with the standard header,
+// or when the first non-comment line has 7 tab separated fields.
+func IsNetscapeCookies(raw string) bool {
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, _netscapeHeader) || strings.HasPrefix(trimmed, _netscapeHeaderShort) {
+		return true
+	}
+
+	for _, line := range strings.Split(trimmed, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" || (strings.HasPrefix(line, "#") && !strings.HasPrefix(line, _netscapeHTTPOnly)) {
+			continue
+		}
+
+		return len(strings.Split(line, "\t")) == _netscapeFieldsLen
+	}
+
+	return false
+}
+
+// ParseNetscapeCookies converts a Netscape `cookies.txt` content into NetworkCookieParam objects.
+//
+// Each non-comment line has 7 tab separated fields:
+//
+//	domain  include_subdomains  path  secure  expiry  name  value
+//
+// Lines prefixed with `#HttpOnly_` are httpOnly cookies, an expiry of 0 means a session cookie.
+// Cookies with include_subdomains FALSE are host-only, they're set by URL instead of Domain.
+//
+// Returns ErrInvalidNetscapeCookie with the line number if any line is malformed.
+func ParseNetscapeCookies(raw string) ([]*proto.NetworkCookieParam, error) {
+	var nodes []*proto.NetworkCookieParam
+
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	lineNo := 0
+
+	for scanner.Scan() {
+		lineNo++
+
+		line := strings.TrimRight(scanner.Text(), "\r")
+		httpOnly := false
+
+		if strings.HasPrefix(line, _netscapeHTTPOnly) {
+			httpOnly = true
+			line = strings.TrimPrefix(line, _netscapeHTTPOnly)
+		}
+
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		node, err := parseNetscapeLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidNetscapeCookie, lineNo, err)
+		}
+
+		node.HTTPOnly = httpOnly
+		nodes = append(nodes, node)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(nodes) == 0 {
+		return nil, ErrEmptyCookieStr
+	}
+
+	return nodes, nil
+}
+
+func parseNetscapeLine(line string) (*proto.NetworkCookieParam, error) {
+	fields := strings.Split(line, "\t")
+	if len(fields) != _netscapeFieldsLen {
+		return nil, fmt.Errorf("want %d tab separated fields, got %d", _netscapeFieldsLen, len(fields))
+	}
+
+	domain, subdomains, path, secure, expiry, name, value := fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6]
+
+	if domain == "" || name == "" {
+		return nil, errors.New("empty domain or name")
+	}
+
+	includeSubdomains, err := parseNetscapeBool(subdomains)
+	if err != nil {
+		return nil, fmt.Errorf("include_subdomains: %w", err)
+	}
+
+	isSecure, err := parseNetscapeBool(secure)
+	if err != nil {
+		return nil, fmt.Errorf("secure: %w", err)
+	}
+
+	expires, err := strconv.ParseFloat(expiry, 64)
+	if err != nil {
+		return nil, fmt.Errorf("expiry: %w", err)
+	}
+
+	node := &proto.NetworkCookieParam{
+		Name:   name,
+		Value:  value,
+		Path:   StrAorB(path, "/"),
+		Secure: isSecure,
+	}
+
+	// chrome makes a cookie set by url without domain host-only, with domain it's sent to subdomains too.
+	if includeSubdomains {
+		node.Domain = "." + strings.TrimPrefix(domain, ".")
+	} else {
+		scheme := _schemeHTTP
+		if isSecure {
+			scheme = _schemeHTTPS
+		}
+
+		hostURL := &url.URL{Scheme: scheme, Host: strings.TrimPrefix(domain, "."), Path: node.Path}
+		node.URL = hostURL.String()
+	}
+
+	if expires > _netscapeSessionStamp {
+		node.Expires = proto.TimeSinceEpoch(expires)
+	}
+
+	return node, nil
+}
+
+func parseNetscapeBool(s string) (bool, error) {
+	switch strings.ToUpper(s) {
+	case _netscapeTrue:
+		return true, nil
+	case _netscapeFalse:
+		return false, nil
+	default:
+		return false, fmt.Errorf("want TRUE or FALSE, got %q", s)
+	}
+}
+
+// FormatNetscapeCookies renders cookies in the Netscape `cookies.txt` format,
+// session cookies are written with an expiry of 0.
+func FormatNetscapeCookies(cookies []*proto.NetworkCookie) string {
+	var sb strings.Builder
+
+	sb.WriteString(_netscapeHeader + "\n\n")
+
+	for _, c := range cookies {
+		domain := c.Domain
+		if c.HTTPOnly {
+			domain = _netscapeHTTPOnly + domain
+		}
+
+		expires := int64(_netscapeSessionStamp)
+		if !c.Session && c.Expires > 0 {
+			expires = int64(c.Expires)
+		}
+
+		fmt.Fprintf(&sb, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			domain,
+			netscapeBool(strings.HasPrefix(c.Domain, ".")),
+			StrAorB(c.Path, "/"),
+			netscapeBool(c.Secure),
+			expires,
+			c.Name,
+			c.Value,
+		)
+	}
+
+	return sb.String()
+}
+
+func netscapeBool(b bool) string {
+	if b {
+		return _netscapeTrue
+	}
+
+	return _netscapeFalse
+}
+
+// DumpNetscapeCookies saves the current cookies of the bot's page to filepath in Netscape `cookies.txt` format,
+// so the session can be shared with curl (`-b`), wget (`--load-cookies`) or yt-dlp (`--cookies`).
+//
+// If filepath is empty, the cookie filename is generated from current URL with suffix `.txt`.
+// The file is never encrypted, other tools cannot read it otherwise, but it's written with 0600 permissions.
+//
+// Returns the file path where the cookies were saved.
+func (b *Bot) DumpNetscapeCookies(filepath string) (string, error) {
+	if filepath == "" {
+		file, err := b.createCookieFilenameFromURL(b.CurrentURL())
+		if err != nil {
+			return "", err
+		}
+
+		filepath = file + ".txt"
+	}
+
+	if err := fsutil.MkParentDir(filepath); err != nil {
+		return "", err
+	}
+
+	cookies, err := b.page.Cookies(nil)
+	if err != nil {
+		return "", fmt.Errorf("cannot get cookies: %w", err)
+	}
+
+	if err := writeSecretFile(filepath, []byte(FormatNetscapeCookies(cookies))); err != nil {
+		return "", fmt.Errorf("cannot save file: %w", err)
+	}
+
+	return filepath, nil
+}
+
+// ParseNetscapeCookiesFromFile reads a Netscape `cookies.txt` file and returns NetworkCookieParam objects.
+func (b *Bot) ParseNetscapeCookiesFromFile(filePath string) ([]*proto.NetworkCookieParam, error) {
+	raw, err := b.readCookieFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseNetscapeCookies(string(raw))
+}
@@ -0,0 +1,88 @@
+package wee
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/suite"
+)
+
+type NetscapeCookieSuite struct {
+	suite.Suite
+}
+
+func TestNetscapeCookie(t *testing.T) {
+	suite.Run(t, new(NetscapeCookieSuite))
+}
+
+const _netscapeFixture = `# Netscape HTTP Cookie File
+# https://curl.se/docs/http-cookies.html
+
+.example.com	TRUE	/	TRUE	1893456000	token	a=b==
+#HttpOnly_www.example.com	FALSE	/app	FALSE	0	sid	s001
+`
+
+func (s *NetscapeCookieSuite) TestParse() {
+	s.True(IsNetscapeCookies(_netscapeFixture))
+	s.False(IsNetscapeCookies(`curl 'https://example.com' -H 'Cookie: a=1'`))
+
+	nodes, err := ParseNetscapeCookies(_netscapeFixture)
+	s.Require().NoError(err)
+	s.Require().Len(nodes, 2)
+
+	s.Equal(&proto.NetworkCookieParam{
+		Name:    "token",
+		Value:   "a=b==",
+		Domain:  ".example.com",
+		Path:    "/",
+		Secure:  true,
+		Expires: 1893456000,
+	}, nodes[0])
+
+	s.Equal(&proto.NetworkCookieParam{
+		Name:     "sid",
+		Value:    "s001",
+		URL:      "http://www.example.com/app",
+		Path:     "/app",
+		HTTPOnly: true,
+	}, nodes[1], "host-only")
+}
+
+func (s *NetscapeCookieSuite) TestParseInvalid() {
+	_, err := ParseNetscapeCookies("example.com\tTRUE\t/\tYES\t0\tname\tvalue")
+	s.ErrorIs(err, ErrInvalidNetscapeCookie)
+	s.Contains(err.Error(), "line 1")
+
+	_, err = ParseNetscapeCookies("# Netscape HTTP Cookie File\n")
+	s.ErrorIs(err, ErrEmptyCookieStr)
+}
+
+func (s *NetscapeCookieSuite) TestRoundTrip() {
+	cookies := []*proto.NetworkCookie{
+		{Name: "token", Value: "a=b==", Domain: ".example.com", Path: "/", Secure: true, Expires: 1893456000},
+		{Name: "sid", Value: "s001", Domain: "www.example.com", Path: "/app", HTTPOnly: true, Session: true, Expires: -1},
+	}
+
+	raw := FormatNetscapeCookies(cookies)
+	s.Equal(_netscapeFixture[:len(_netscapeHeader)], raw[:len(_netscapeHeader)])
+
+	nodes, err := ParseNetscapeCookies(raw)
+	s.Require().NoError(err)
+	s.Require().Len(nodes, 2)
+
+	for i, c := range cookies {
+		s.Equal(c.Name, nodes[i].Name)
+		s.Equal(c.Value, nodes[i].Value)
+		if nodes[i].Domain == "" {
+			s.Contains(nodes[i].URL, "://"+c.Domain+"/", "host-only")
+		} else {
+			s.Equal(c.Domain, nodes[i].Domain)
+		}
+		s.Equal(c.Path, nodes[i].Path)
+		s.Equal(c.Secure, nodes[i].Secure)
+		s.Equal(c.HTTPOnly, nodes[i].HTTPOnly)
+	}
+
+	s.Equal(proto.TimeSinceEpoch(1893456000), nodes[0].Expires)
+	s.Zero(nodes[1].Expires)
+}
@@ -3,6 +3,7 @@ package wee
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -186,39 +187,72 @@ func (b *Bot) ElemByIndex(selector string, index int) (*rod.Element, error) {
 //   - Elem: For standard CSS selector-based element selection.
 //   - ElemsByText: For selecting multiple elements by text content.
 func (b *Bot) ElemByText(selector string, opts ...ElemOptionFunc) (*rod.Element, error) {
-	opt := ElemOptions{root: b.root, timeout: ShortToSec}
+	opt := ElemOptions{root: b.root, timeout: b.shortTimeout.Seconds()}
 	bindElemOptions(&opt, opts...)
 
+	ts, err := parseTextSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	txt := ts.jsRegex(opt.caseInsensitive)
+
+	var elem *rod.Element
+
+	dur := secToDuration(opt.timeout)
+
+	if opt.root != nil {
+		elem, err = opt.root.Timeout(dur).ElementR(ts.css, txt)
+	} else {
+		elem, err = b.page.Timeout(dur).ElementR(ts.css, txt)
+	}
+
+	return elem, err
+}
+
+// textSelector is a selector of ElemByText, "css@@@text" matches the text by regex,
+// three parts like "css@@@---@@@text" match the whole text.
+type textSelector struct {
+	css   string
+	txt   string
+	exact bool
+}
+
+func parseTextSelector(selector string) (*textSelector, error) {
 	arr := strings.Split(selector, SEP)
 	if len(arr) < _textPartialMatch {
 		return nil, ErrInvalidByTextFormat
 	}
 
-	txt := arr[len(arr)-1]
+	return &textSelector{css: arr[0], txt: arr[len(arr)-1], exact: len(arr) == _textExactMatchLen}, nil
+}
 
-	if len(arr) == _textExactMatchLen {
-		m := "/^%s$/"
-		if opt.caseInsensitive {
-			m += "i"
-		}
+// jsRegex is the regex of rod's ElementR, case insensitive only applies to exact match.
+func (ts *textSelector) jsRegex(caseInsensitive bool) string {
+	if !ts.exact {
+		return ts.txt
+	}
 
-		txt = fmt.Sprintf(m, txt)
+	m := "/^%s$/"
+	if caseInsensitive {
+		m += "i"
 	}
 
-	var (
-		elem *rod.Element
-		err  error
-	)
+	return fmt.Sprintf(m, ts.txt)
+}
 
-	dur := time.Duration(opt.timeout) * time.Second
+// regexp is jsRegex for elements already found, e.g. by Extract.
+func (ts *textSelector) regexp(caseInsensitive bool) (*regexp.Regexp, error) {
+	if !ts.exact {
+		return regexp.Compile(ts.txt)
+	}
 
-	if opt.root != nil {
-		elem, err = opt.root.Timeout(dur).ElementR(arr[0], txt)
-	} else {
-		elem, err = b.page.Timeout(dur).ElementR(arr[0], txt)
+	m := "^%s$"
+	if caseInsensitive {
+		m = "(?i)" + m
 	}
 
-	return elem, err
+	return regexp.Compile(fmt.Sprintf(m, ts.txt))
 }
 
 // ElemsByText finds all elements by their text content using a combination of CSS selector and text matching.
@@ -439,7 +473,7 @@ func (b *Bot) elems(selector string, opts ...ElemOptionFunc) ([]*rod.Element, er
 		return []*rod.Element{elem}, nil
 	}
 
-	opt := ElemOptions{timeout: NapToSec}
+	opt := ElemOptions{timeout: b.napTimeout.Seconds()}
 	bindElemOptions(&opt, opts...)
 
 	if opt.timeout != 0 {
@@ -577,7 +611,7 @@ func (b *Bot) getElem(selector string, opts ...ElemOptionFunc) (*rod.Element, er
 		return nil, ErrSelectorEmpty
 	}
 
-	opt := ElemOptions{root: b.root, timeout: ShortToSec}
+	opt := ElemOptions{root: b.root, timeout: b.shortTimeout.Seconds()}
 	bindElemOptions(&opt, opts...)
 
 	if ss := strings.Split(selector, IFrameSep); len(ss) == _iframeLen {
@@ -599,7 +633,7 @@ func (b *Bot) getElem(selector string, opts ...ElemOptionFunc) (*rod.Element, er
 		err  error
 	)
 
-	dur := time.Duration(opt.timeout) * time.Second
+	dur := secToDuration(opt.timeout)
 
 	// this will wait until element shown, or got error
 	if opt.root != nil {
@@ -807,7 +841,7 @@ func (b *Bot) MustAnyElem(selectors []string, opts ...ElemOptionFunc) string {
 //     other issues occurred during the search.
 //
 // Behavior:
-//  1. Sets up default options (timeout: MediumToSec, retries: 1) which can be overridden.
+//  1. Sets up default options (timeout: the bot's medium timeout, retries: 1) which can be overridden.
 //  2. Uses Rod's Race method to concurrently search for all provided selectors.
 //  3. Returns as soon as any of the selectors matches an element.
 //  4. If no element is found within the timeout, it retries based on the retry option.
@@ -843,7 +877,7 @@ func (b *Bot) MustAnyElem(selectors []string, opts ...ElemOptionFunc) string {
 //   - Elem: For finding a single specific element.
 //   - AnyElemAttribute: If you need to retrieve an attribute from the found element.
 func (b *Bot) AnyElem(selectors []string, opts ...ElemOptionFunc) (string, error) {
-	opt := ElemOptions{timeout: MediumToSec, retries: 1}
+	opt := ElemOptions{timeout: b.mediumTimeout.Seconds(), retries: 1}
 	bindElemOptions(&opt, opts...)
 
 	var (
@@ -854,7 +888,7 @@ func (b *Bot) AnyElem(selectors []string, opts ...ElemOptionFunc) (string, error
 	err = retry.Do(
 		func() error {
 			// err = rod.Try(func() {
-			race := b.page.Timeout(time.Duration(opt.timeout) * time.Second).Race()
+			race := b.page.Timeout(secToDuration(opt.timeout)).Race()
 			for _, s := range selectors {
 				b.appendToRace(s, &sel, race)
 			}
@@ -865,6 +899,7 @@ func (b *Bot) AnyElem(selectors []string, opts ...ElemOptionFunc) (string, error
 		},
 		retry.Attempts(opt.retries),
 		retry.LastErrorOnly(true),
+		retry.Context(b.Context()),
 	)
 
 	return sel, err
@@ -1169,7 +1204,7 @@ func (b *Bot) TryFocusElement(elem *rod.Element, scrollToRight bool) error {
 //   - OpenInNewTab: Called when opening the element in a new tab.
 //   - ClickElem: Used for standard click interactions.
 func (b *Bot) OpenOrClickElement(elem *rod.Element, opts ...ElemOptionFunc) error {
-	opt := ElemOptions{root: b.root, timeout: ShortToSec}
+	opt := ElemOptions{root: b.root, timeout: b.shortTimeout.Seconds()}
 	bindElemOptions(&opt, opts...)
 
 	if err := b.TryFocusElement(elem, true); err != nil {
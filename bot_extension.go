@@ -1,10 +1,18 @@
 package wee
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/gookit/goutil/fsutil"
@@ -26,6 +34,11 @@ import (
 // Example:
 //
 //	extDir, err := NewChromeExtension("192.168.1.1:8080:user:pass", "/path/to/extensions")
+//
+// Note:
+//   - Extensions are not loaded in headless mode, and Manifest V2 is being removed from Chrome,
+//     BrowserProxy("user:pass@host:port") answers the proxy auth challenges without extension.
+//   - For a Manifest V3 extension with scheme, bypass list or PAC script, see NewProxyExtension.
 func NewChromeExtension(line, savePath string) (string, error) {
 	proxyJS := `var config = {
   mode: 'fixed_servers',
@@ -94,3 +107,216 @@ func writeFile(filepath string, content []byte) error {
 
 	return os.WriteFile(filepath, content, mode)
 }
+
+var ErrInvalidProxyConfig = errors.New("invalid proxy config")
+
+// _proxySchemes are schemes of the chrome.proxy api, chrome cannot authenticate to socks proxies.
+var _proxySchemes = map[string]bool{"http": true, "https": true, "socks4": true, "socks5": true}
+
+// ProxyConfig is the proxy of NewProxyExtension.
+type ProxyConfig struct {
+	// Scheme is http, https, socks4 or socks5, default is http.
+	Scheme string `json:"scheme"`
+	// Host is a hostname or an ip, IPv6 with or without brackets.
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	// Username and Password are answered to the proxy auth challenges, http and https only.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// BypassList are hosts connected directly, e.g. "localhost", "*.example.com", "<local>".
+	BypassList []string `json:"bypassList,omitempty"`
+	// PACScript is the content of a PAC script, it's used instead of Scheme/Host/Port/BypassList if set.
+	PACScript string `json:"pacScript,omitempty"`
+}
+
+// ParseProxyConfig parses "[scheme://][user:pass@]host:port", credentials with "@" or ":" must be url escaped.
+//
+// Usage:
+//
+//	cfg, err := wee.ParseProxyConfig("socks5://[2001:db8::1]:1080")
+func ParseProxyConfig(proxy string) (*ProxyConfig, error) {
+	raw := proxy
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProxyConfig, err)
+	}
+
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid port of %q", ErrInvalidProxyConfig, proxy)
+	}
+
+	cfg := &ProxyConfig{Scheme: u.Scheme, Host: u.Hostname(), Port: port}
+	if u.User != nil {
+		cfg.Username = u.User.Username()
+		cfg.Password, _ = u.User.Password()
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// Validate checks the config before it's written into an extension.
+func (c *ProxyConfig) Validate() error {
+	if c.PACScript != "" {
+		return nil
+	}
+
+	if !_proxySchemes[StrAorB(c.Scheme, "http")] {
+		return fmt.Errorf("%w: unsupported scheme %q", ErrInvalidProxyConfig, c.Scheme)
+	}
+
+	host := strings.Trim(c.Host, "[]")
+	if host == "" || strings.ContainsAny(host, " /@?#") {
+		return fmt.Errorf("%w: invalid host %q", ErrInvalidProxyConfig, c.Host)
+	}
+
+	if strings.Contains(host, ":") && net.ParseIP(host) == nil {
+		return fmt.Errorf("%w: invalid IPv6 host %q", ErrInvalidProxyConfig, c.Host)
+	}
+
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("%w: invalid port %d", ErrInvalidProxyConfig, c.Port)
+	}
+
+	if c.Username != "" && strings.HasPrefix(c.Scheme, "socks") {
+		return fmt.Errorf("%w: chrome cannot authenticate to %s proxies", ErrInvalidProxyConfig, c.Scheme)
+	}
+
+	return nil
+}
+
+// NewProxyExtension creates a Manifest V3 extension which sets the proxy, and answers its auth challenges.
+//
+// The extension is written to savePath/proxy_<hash of config>, so the same config always gets the same dir,
+// it's loaded by BrowserExtensions, in headed mode only since extensions are not loaded in headless mode.
+// To use a proxy with credentials in headless mode, see BrowserProxy.
+//
+// Usage:
+//
+//	dir, err := wee.NewProxyExtension(wee.ProxyConfig{
+//	    Scheme:     "http",
+//	    Host:       "proxy.example.com",
+//	    Port:       8000,
+//	    Username:   "user",
+//	    Password:   "pass:with:colons",
+//	    BypassList: []string{"<local>", "*.internal.example.com"},
+//	}, "/tmp/extensions")
+//
+//	l, brw := wee.NewBrowser(wee.BrowserExtensions(dir))
+func NewProxyExtension(cfg ProxyConfig, savePath string) (string, error) {
+	if err := cfg.Validate(); err != nil {
+		return "", err
+	}
+
+	manifest, background, err := cfg.extensionFiles()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(append(manifest, background...))
+	baseDir := filepath.Join(savePath, "proxy_"+hex.EncodeToString(sum[:])[:12])
+
+	if err := writeFile(filepath.Join(baseDir, "manifest.json"), manifest); err != nil {
+		return "", err
+	}
+
+	// background.js has the credentials, it's readable by the owner only like cookie files.
+	if err := writeSecretFile(filepath.Join(baseDir, "background.js"), background); err != nil {
+		return "", err
+	}
+
+	return baseDir, nil
+}
+
+func (c *ProxyConfig) extensionFiles() (manifest, background []byte, err error) {
+	manifest, err = json.MarshalIndent(map[string]any{
+		"manifest_version":       3, //nolint:mnd
+		"name":                   "WeeProxy",
+		"version":                "1.0.0",
+		"permissions":            []string{"proxy", "webRequest", "webRequestAuthProvider"},
+		"host_permissions":       []string{"<all_urls>"},
+		"background":             map[string]string{"service_worker": "background.js"},
+		"minimum_chrome_version": "120", // for 'asyncBlocking' of onAuthRequired in manifest v3
+	}, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// values are embedded as json, so quotes in passwords or scripts cannot break the script.
+	settings, err := jsValue(c.settings())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	credentials, err := jsValue(map[string]string{"username": c.Username, "password": c.Password})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	background = fmt.Appendf(nil, `const config = %s;
+const credentials = %s;
+
+chrome.proxy.settings.set({ value: config, scope: 'regular' }, () => {});
+
+chrome.webRequest.onAuthRequired.addListener(
+  (details, callback) => {
+    if (details.isProxy && credentials.username) {
+      callback({ authCredentials: credentials });
+    } else {
+      callback({});
+    }
+  },
+  { urls: ['<all_urls>'] },
+  ['asyncBlocking'],
+);
+`, settings, credentials)
+
+	return manifest, background, nil
+}
+
+// settings is the value of chrome.proxy.settings.
+func (c *ProxyConfig) settings() map[string]any {
+	if c.PACScript != "" {
+		return map[string]any{
+			"mode":      "pac_script",
+			"pacScript": map[string]string{"data": c.PACScript},
+		}
+	}
+
+	host := c.Host
+	if strings.Contains(host, ":") && !strings.HasPrefix(host, "[") {
+		host = "[" + host + "]"
+	}
+
+	rules := map[string]any{
+		"singleProxy": map[string]any{
+			"scheme": StrAorB(c.Scheme, "http"),
+			"host":   host,
+			"port":   c.Port,
+		},
+	}
+
+	if len(c.BypassList) != 0 {
+		rules["bypassList"] = c.BypassList
+	}
+
+	return map[string]any{"mode": "fixed_servers", "rules": rules}
+}
+
+// jsValue encodes v as a js literal, without escaping html like "<local>" of bypass lists.
+func jsValue(v any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
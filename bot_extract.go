@@ -0,0 +1,555 @@
+package wee
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+const (
+	// ExtractTag is the struct tag read by Extract.
+	ExtractTag = "wee"
+
+	// _extractHTML is the attr of Extract reading the outer html.
+	_extractHTML = "html"
+)
+
+var (
+	ErrInvalidExtractTarget = errors.New("extract target must be a pointer to a struct or a slice of structs")
+	ErrInvalidExtractTag    = errors.New("invalid extract tag")
+	ErrRequiredField        = errors.New("required field not found")
+	ErrNoExtractItem        = errors.New("no item to extract")
+)
+
+var _timeType = reflect.TypeOf(time.Time{})
+
+// FieldError is the error of one field of one item, see Extract.
+type FieldError struct {
+	// Item is the index of the item, nested items are not counted.
+	Item int
+	// Field is the path of the field, e.g. "Seller.Name".
+	Field    string
+	Selector string
+	Err      error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("item %d: %s (%s): %v", e.Item, e.Field, e.Selector, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ExtractErrors are all field errors of Extract, errors.Is and errors.As see each of them.
+type ExtractErrors []*FieldError
+
+func (e ExtractErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Error())
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+func (e ExtractErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, fe := range e {
+		errs = append(errs, fe)
+	}
+
+	return errs
+}
+
+// extractSpec is how to read one field.
+type extractSpec struct {
+	// css is relative to the item, "" is the item itself, "css@@@text" filters by text like ElemByText.
+	css string
+	// caseInsensitive matches the text of "css@@@---@@@text" ignoring case, like WithCaseInsensitive.
+	caseInsensitive bool
+	// attr is read by elem.Attribute, "" is innerText, "html" is the outer html.
+	attr string
+	// prop is read by elem.Property, e.g. "href" is the absolute url.
+	prop     string
+	def      string
+	hasDef   bool
+	required bool
+	// keep are chars kept besides digits when a number is parsed, see StrToNumChars.
+	keep    string
+	hasKeep bool
+	// layout of time.Time fields, default is time.RFC3339.
+	layout string
+}
+
+// Extract reads the items matched by rootSelector into out, which is a pointer to a slice of structs,
+// or to one struct filled from the first match.
+//
+// Fields are read by their `wee` tag, a list of "key=value" separated by ";":
+//   - css: selector relative to the item, e.g. "a.header", "span@@@Price" or "span@@@---@@@Price" (by text),
+//     the item itself if not set. Text is matched like ElemByText, by regex, or the whole text with three parts.
+//   - caseInsensitive: the whole text of three parts selectors is matched ignoring case.
+//   - attr: attribute to read, innerText by default, "html" is the outer html.
+//   - prop: property to read instead of attr, e.g. "href" for the absolute url.
+//   - default: value used when the element is not found or its value is empty.
+//   - required: the field must be found and not empty, else a FieldError wrapping ErrRequiredField.
+//   - keep: chars kept besides digits when parsing numbers, "." for floats by default, see MustStrToFloat.
+//   - layout: layout of time.Time fields, time.RFC3339 by default.
+//
+// Supported field types are strings, bools, ints, uints, floats, time.Time, nested structs and pointers to them,
+// and slices of them, which read all matches of css. Fields without tag or tagged with "-" are skipped.
+//
+// Conversion errors and missing required fields don't stop the extraction,
+// they're returned together as ExtractErrors, and items are kept with the fields which can be read.
+//
+// Usage:
+//
+//	type Job struct {
+//	    Title    string    `wee:"css=a.header;required"`
+//	    URL      string    `wee:"css=a.header;prop=href"`
+//	    Salary   float64   `wee:"css=span@@@Salary;default=0"`
+//	    Tags     []string  `wee:"css=ul.tags li"`
+//	    Posted   time.Time `wee:"css=time;attr=datetime;layout=2006-01-02"`
+//	    Company  struct {
+//	        Name string `wee:"css=.name"`
+//	    } `wee:"css=div.company"`
+//	}
+//
+//	var jobs []Job
+//	err := bot.Extract("div.job-item", &jobs)
+func (b *Bot) Extract(rootSelector string, out any, opts ...ElemOptionFunc) error {
+	target := reflect.ValueOf(out)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return ErrInvalidExtractTarget
+	}
+
+	target = target.Elem()
+
+	single := target.Kind() == reflect.Struct
+	if !single && (target.Kind() != reflect.Slice || !isStructType(target.Type().Elem())) {
+		return ErrInvalidExtractTarget
+	}
+
+	var (
+		items []*rod.Element
+		err   error
+	)
+
+	if rootSelector == "" {
+		var html *rod.Element
+
+		html, err = b.page.Element("html")
+		items = []*rod.Element{html}
+	} else {
+		items, err = b.Elems(rootSelector, opts...)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if single {
+		if len(items) == 0 {
+			return fmt.Errorf("%w: %s", ErrNoExtractItem, rootSelector)
+		}
+
+		items = items[:1]
+	}
+
+	itemType := target.Type()
+	if !single {
+		itemType = derefType(itemType.Elem())
+	}
+
+	var errs ExtractErrors
+
+	values := reflect.MakeSlice(reflect.SliceOf(itemType), 0, len(items))
+
+	for i, item := range items {
+		v := reflect.New(itemType).Elem()
+		if single {
+			v = target
+		}
+
+		ex := &extractor{item: i}
+		ex.fillStruct(item, v, "")
+		errs = append(errs, ex.errs...)
+
+		values = reflect.Append(values, v)
+	}
+
+	if !single {
+		setSlice(target, values)
+	}
+
+	if len(errs) != 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (b *Bot) MustExtract(rootSelector string, out any, opts ...ElemOptionFunc) {
+	b.pie(b.Extract(rootSelector, out, opts...))
+}
+
+type extractor struct {
+	item int
+	errs ExtractErrors
+}
+
+func (ex *extractor) fail(field, selector string, err error) {
+	ex.errs = append(ex.errs, &FieldError{Item: ex.item, Field: field, Selector: selector, Err: err})
+}
+
+func (ex *extractor) fillStruct(elem *rod.Element, v reflect.Value, prefix string) {
+	typ := v.Type()
+
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+
+		tag, ok := field.Tag.Lookup(ExtractTag)
+		if !ok || tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		name := prefix + field.Name
+
+		spec, err := parseExtractTag(tag)
+		if err != nil {
+			ex.fail(name, tag, err)
+			continue
+		}
+
+		ex.fillField(elem, v.Field(i), name, spec)
+	}
+}
+
+func (ex *extractor) fillField(elem *rod.Element, v reflect.Value, name string, spec *extractSpec) {
+	elems, err := findElems(elem, spec)
+	if err != nil {
+		ex.fail(name, spec.css, err)
+		return
+	}
+
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(v.Type(), 0, len(elems))
+
+		for i, el := range elems {
+			item := reflect.New(v.Type().Elem()).Elem()
+			if ex.fillValue(el, item, fmt.Sprintf("%s[%d]", name, i), spec) {
+				slice = reflect.Append(slice, item)
+			}
+		}
+
+		if len(elems) == 0 && spec.required {
+			ex.fail(name, spec.css, ErrRequiredField)
+		}
+
+		v.Set(slice)
+
+		return
+	}
+
+	if len(elems) == 0 {
+		ex.fillMissing(v, name, spec)
+		return
+	}
+
+	ex.fillValue(elems[0], v, name, spec)
+}
+
+// fillValue sets v from elem, it returns false if v is not set.
+func (ex *extractor) fillValue(elem *rod.Element, v reflect.Value, name string, spec *extractSpec) bool {
+	if v.Kind() == reflect.Pointer {
+		ptr := reflect.New(v.Type().Elem())
+		if !ex.fillValue(elem, ptr.Elem(), name, spec) {
+			return false
+		}
+
+		v.Set(ptr)
+
+		return true
+	}
+
+	if isStructType(v.Type()) {
+		ex.fillStruct(elem, v, name+".")
+		return true
+	}
+
+	raw, err := readElemValue(elem, spec)
+	if err != nil {
+		ex.fail(name, spec.css, err)
+		return false
+	}
+
+	if raw == "" {
+		return ex.fillMissing(v, name, spec)
+	}
+
+	if err := setExtractValue(v, raw, spec); err != nil {
+		ex.fail(name, spec.css, err)
+		return false
+	}
+
+	return true
+}
+
+// fillMissing sets the default value of a field not found or empty.
+func (ex *extractor) fillMissing(v reflect.Value, name string, spec *extractSpec) bool {
+	switch {
+	case spec.hasDef:
+		if v.Kind() == reflect.Pointer {
+			v.Set(reflect.New(v.Type().Elem()))
+			v = v.Elem()
+		}
+
+		if err := setExtractValue(v, spec.def, spec); err != nil {
+			ex.fail(name, spec.css, err)
+			return false
+		}
+
+		return true
+	case spec.required:
+		ex.fail(name, spec.css, ErrRequiredField)
+	}
+
+	return false
+}
+
+func parseExtractTag(tag string) (*extractSpec, error) {
+	spec := &extractSpec{}
+
+	for _, part := range strings.Split(tag, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+
+		switch key {
+		case "":
+		case "css":
+			spec.css = value
+		case "attr":
+			spec.attr = value
+		case "prop":
+			spec.prop = value
+		case "default":
+			spec.def, spec.hasDef = value, true
+		case "required":
+			spec.required = true
+		case "caseInsensitive":
+			spec.caseInsensitive = true
+		case "keep":
+			spec.keep, spec.hasKeep = value, true
+		case "layout":
+			spec.layout = value
+		default:
+			return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidExtractTag, key)
+		}
+	}
+
+	return spec, nil
+}
+
+// findElems finds elements of spec.css in root, "" is root itself.
+// Selectors with SEP are matched by text as ElemByText does.
+func findElems(root *rod.Element, spec *extractSpec) ([]*rod.Element, error) {
+	if spec.css == "" {
+		return []*rod.Element{root}, nil
+	}
+
+	if !strings.Contains(spec.css, SEP) {
+		return root.Elements(spec.css)
+	}
+
+	ts, err := parseTextSelector(spec.css)
+	if err != nil {
+		return nil, err
+	}
+
+	reg, err := ts.regexp(spec.caseInsensitive)
+	if err != nil {
+		return nil, err
+	}
+
+	elems, err := root.Elements(ts.css)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*rod.Element
+
+	for _, elem := range elems {
+		text, err := elem.Text()
+		if err != nil {
+			continue
+		}
+
+		if reg.MatchString(text) {
+			matched = append(matched, elem)
+		}
+	}
+
+	return matched, nil
+}
+
+func readElemValue(elem *rod.Element, spec *extractSpec) (string, error) {
+	switch {
+	case spec.prop != "":
+		prop, err := elem.Property(spec.prop)
+		if err != nil {
+			return "", err
+		}
+
+		if prop.Nil() {
+			return "", nil
+		}
+
+		return strings.TrimSpace(prop.String()), nil
+	case spec.attr == _extractHTML:
+		return elem.HTML()
+	case spec.attr != "" && spec.attr != "innerText":
+		attr, err := elem.Attribute(spec.attr)
+		if err != nil || attr == nil {
+			return "", err
+		}
+
+		return strings.TrimSpace(*attr), nil
+	}
+
+	text, err := elem.Text()
+
+	return strings.TrimSpace(text), err
+}
+
+// setExtractValue converts raw to the type of v.
+func setExtractValue(v reflect.Value, raw string, spec *extractSpec) error {
+	if v.Type() == _timeType {
+		t, err := time.Parse(StrAorB(spec.layout, time.RFC3339), raw)
+		if err != nil {
+			return err
+		}
+
+		v.Set(reflect.ValueOf(t))
+
+		return nil
+	}
+
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Slice:
+		// []byte
+		v.SetBytes([]byte(raw))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		num, err := intChars(raw, spec)
+		if err != nil {
+			return err
+		}
+
+		n, err := strconv.ParseInt(num, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		num, err := intChars(raw, spec)
+		if err != nil {
+			return err
+		}
+
+		n, err := strconv.ParseUint(num, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(floatChars(raw, spec), v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("%w: unsupported type %s", ErrInvalidExtractTag, v.Type())
+	}
+
+	return nil
+}
+
+// numChars cleans raw like MustStrToFloat, e.g. "1 234,50 kr" with keep "," is "1234,50".
+// A leading minus sign is kept.
+func numChars(raw string, spec *extractSpec, keep string) string {
+	if spec.hasKeep {
+		keep = spec.keep
+	}
+
+	// kept chars come from struct tags, they're literal chars, not a part of the regexp.
+	num := StrToNumChars(raw, regexp.QuoteMeta(keep))
+	if strings.HasPrefix(strings.TrimSpace(raw), "-") {
+		num = "-" + num
+	}
+
+	return num
+}
+
+// intChars keeps the decimal point, so "4.5" is refused instead of read as 45.
+func intChars(raw string, spec *extractSpec) (string, error) {
+	num := numChars(raw, spec, ".")
+	if strings.ContainsAny(num, ".,") {
+		return "", fmt.Errorf("%w: %q has a decimal part", strconv.ErrSyntax, raw)
+	}
+
+	return num, nil
+}
+
+// floatChars reads a decimal comma as a point, e.g. "1234,50" is 1234.5.
+func floatChars(raw string, spec *extractSpec) string {
+	num := numChars(raw, spec, ".")
+	if !strings.Contains(num, ".") {
+		num = strings.Replace(num, ",", ".", 1)
+	}
+
+	return num
+}
+
+func isStructType(typ reflect.Type) bool {
+	typ = derefType(typ)
+	return typ.Kind() == reflect.Struct && typ != _timeType
+}
+
+func derefType(typ reflect.Type) reflect.Type {
+	if typ.Kind() == reflect.Pointer {
+		return typ.Elem()
+	}
+
+	return typ
+}
+
+// setSlice sets values of struct into target, which is a slice of structs or pointers to them.
+func setSlice(target, values reflect.Value) {
+	if target.Type().Elem().Kind() != reflect.Pointer {
+		target.Set(values)
+		return
+	}
+
+	ptrs := reflect.MakeSlice(target.Type(), values.Len(), values.Len())
+	for i := range values.Len() {
+		ptrs.Index(i).Set(values.Index(i).Addr())
+	}
+
+	target.Set(ptrs)
+}
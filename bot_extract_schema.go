@@ -0,0 +1,517 @@
+package wee
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// Transforms of SchemaField, the value is a string when transform is empty.
+const (
+	TransformInt   = "int"
+	TransformFloat = "float"
+	TransformBool  = "bool"
+	TransformTime  = "time"
+	TransformLower = "lower"
+	TransformUpper = "upper"
+)
+
+var ErrInvalidSchema = errors.New("invalid extract schema")
+
+var _schemaTransforms = map[string]bool{
+	"": true, TransformInt: true, TransformFloat: true, TransformBool: true,
+	TransformTime: true, TransformLower: true, TransformUpper: true,
+}
+
+// ExtractSchema describes the items of a page, it's the file format of ExtractWithSchema.
+//
+//	name: jobs
+//	url: https://jobb.blocket.se/
+//	popovers: ['button.accept-cookies']
+//	root: div.job-item
+//	fields:
+//	  - name: title
+//	    selector: a.header
+//	    required: true
+//	  - name: url
+//	    selector: a.header
+//	    prop: href
+//	  - name: salary
+//	    selector: span@@@Salary
+//	    transform: float
+//	    default: 0
+//	  - name: id
+//	    selector: a.header
+//	    attr: href
+//	    regex: '/jobs/(\d+)'
+//	    transform: int
+//	  - name: tags
+//	    selector: ul.tags li
+//	    multiple: true
+//	pagination:
+//	  next: div.pagination a.next
+//	  max_pages: 5
+type ExtractSchema struct {
+	Name string `json:"name" yaml:"name"`
+	// URL is opened before extracting if set, else the current page is used.
+	URL string `json:"url" yaml:"url"`
+	// Popovers are closed before each page is extracted, see ClosePopovers.
+	Popovers []string `json:"popovers" yaml:"popovers"`
+	// Root is the selector of items.
+	Root   string         `json:"root"   yaml:"root"`
+	Fields []*SchemaField `json:"fields" yaml:"fields"`
+
+	Pagination *SchemaPagination `json:"pagination" yaml:"pagination"`
+}
+
+// SchemaField is one value of an item, see the tags of Extract for the meaning of selector, attr, prop and keep.
+type SchemaField struct {
+	Name     string `json:"name"     yaml:"name"`
+	Selector string `json:"selector" yaml:"selector"`
+	Attr     string `json:"attr"     yaml:"attr"`
+	Prop     string `json:"prop"     yaml:"prop"`
+	// Regex is applied to the value, the first group is kept if any, else the whole match.
+	Regex string `json:"regex" yaml:"regex"`
+	// Transform is one of int, float, bool, time, lower and upper, empty keeps the string.
+	Transform string `json:"transform" yaml:"transform"`
+	Keep      string `json:"keep"      yaml:"keep"`
+	// Layout of time transform, default is time.RFC3339.
+	Layout   string `json:"layout"   yaml:"layout"`
+	Required bool   `json:"required" yaml:"required"`
+	// CaseInsensitive is the caseInsensitive tag of Extract.
+	CaseInsensitive bool `json:"case_insensitive" yaml:"case_insensitive"`
+	// Default is used when the element is not found, or its value is empty or not matched by Regex.
+	Default any `json:"default" yaml:"default"`
+	// Multiple reads all matches into a list.
+	Multiple bool `json:"multiple" yaml:"multiple"`
+	// Fields make the value an object read from the matched element.
+	Fields []*SchemaField `json:"fields" yaml:"fields"`
+
+	spec *extractSpec
+	reg  *regexp.Regexp
+}
+
+// SchemaPagination clicks the next button after each page.
+type SchemaPagination struct {
+	// Next is the selector of the next page button or link,
+	// pagination stops when it's not found, disabled, or clicking it doesn't change the page.
+	Next string `json:"next" yaml:"next"`
+	// MaxPages is the max number of pages extracted, 0 is no limit.
+	MaxPages int `json:"max_pages" yaml:"max_pages"`
+}
+
+// LoadExtractSchema reads a schema from path, the format is chosen by file extension (.json, .yaml, .yml).
+// Unknown keys are reported as errors.
+func LoadExtractSchema(path string) (*ExtractSchema, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read schema: %w", err)
+	}
+
+	var format string
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		format = ConfigFormatJSON
+	case ".yaml", ".yml":
+		format = ConfigFormatYAML
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownConfigFormat, path)
+	}
+
+	return ParseExtractSchema(raw, format)
+}
+
+// ParseExtractSchema parses raw in format (ConfigFormatJSON or ConfigFormatYAML) and validates the result.
+func ParseExtractSchema(raw []byte, format string) (*ExtractSchema, error) {
+	schema := &ExtractSchema{}
+
+	switch format {
+	case ConfigFormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+
+		if err := dec.Decode(schema); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+		}
+	case ConfigFormatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(raw))
+		dec.KnownFields(true)
+
+		if err := dec.Decode(schema); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownConfigFormat, format)
+	}
+
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// Validate checks the schema, and compiles regexes of fields.
+func (s *ExtractSchema) Validate() error {
+	var errs []error
+
+	if s.Root == "" {
+		errs = append(errs, errors.New("root: must not be empty"))
+	}
+
+	if len(s.Fields) == 0 {
+		errs = append(errs, errors.New("fields: must not be empty"))
+	}
+
+	errs = append(errs, validateSchemaFields(s.Fields, "fields")...)
+
+	if p := s.Pagination; p != nil && p.MaxPages < 0 {
+		errs = append(errs, fmt.Errorf("pagination.max_pages: must not be negative, got %d", p.MaxPages))
+	}
+
+	if len(errs) != 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidSchema, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func validateSchemaFields(fields []*SchemaField, path string) []error {
+	var errs []error
+
+	names := make(map[string]bool)
+
+	for i, f := range fields {
+		at := fmt.Sprintf("%s[%d]", path, i)
+
+		if f == nil {
+			errs = append(errs, fmt.Errorf("%s: must not be empty", at))
+			continue
+		}
+
+		switch {
+		case f.Name == "":
+			errs = append(errs, fmt.Errorf("%s.name: must not be empty", at))
+		case names[f.Name]:
+			errs = append(errs, fmt.Errorf("%s.name: duplicated %q", at, f.Name))
+		}
+
+		names[f.Name] = true
+
+		if !_schemaTransforms[f.Transform] {
+			errs = append(errs, fmt.Errorf("%s.transform: unknown value %q", at, f.Transform))
+		}
+
+		if strings.Contains(f.Selector, SEP) {
+			if err := validateTextSelector(f.Selector, f.CaseInsensitive); err != nil {
+				errs = append(errs, fmt.Errorf("%s.selector: %w", at, err))
+			}
+		}
+
+		if f.Regex != "" {
+			reg, err := regexp.Compile(f.Regex)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s.regex: %w", at, err))
+			}
+
+			f.reg = reg
+		}
+
+		f.spec = &extractSpec{
+			css:             f.Selector,
+			attr:            f.Attr,
+			prop:            f.Prop,
+			required:        f.Required,
+			keep:            f.Keep,
+			caseInsensitive: f.CaseInsensitive,
+			hasKeep:         f.Keep != "",
+			layout:          f.Layout,
+		}
+
+		if f.Default != nil {
+			f.spec.def, f.spec.hasDef = fmt.Sprint(f.Default), true
+		}
+
+		errs = append(errs, validateSchemaFields(f.Fields, at+".fields")...)
+	}
+
+	return errs
+}
+
+// ExtractWithSchema extracts the items described by schema into maps keyed by field names,
+// it opens schema.URL if set, and follows the pagination.
+//
+// Values are strings, or int, float64, bool and time.Time by transform, lists are []any and objects map[string]any.
+// Like Extract, field errors don't stop the extraction, they're returned together as ExtractErrors,
+// Item of FieldError counts items of all pages.
+//
+// Usage:
+//
+//	schema, err := wee.LoadExtractSchema("schemas/jobs.yaml")
+//	items, err := bot.ExtractWithSchema(schema)
+func (b *Bot) ExtractWithSchema(schema *ExtractSchema) ([]map[string]any, error) {
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+
+	if schema.URL != "" {
+		if err := b.Open(schema.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		output []map[string]any
+		errs   ExtractErrors
+		seen   string
+	)
+
+	for page := 1; ; page++ {
+		b.ClosePopovers(schema.Popovers...)
+
+		items, err := b.Elems(schema.Root)
+		if err != nil {
+			return output, err
+		}
+
+		// the next button didn't change the page, e.g. it's the last page with a next button handled by javascript.
+		sign := b.schemaPageSign(items)
+		if page > 1 && sign == seen {
+			b.logger.Debug("page is not changed by next", zap.Int("page", page))
+			break
+		}
+
+		seen = sign
+
+		for _, item := range items {
+			ex := &extractor{item: len(output)}
+			output = append(output, ex.schemaObject(item, schema.Fields, ""))
+			errs = append(errs, ex.errs...)
+		}
+
+		if !b.nextSchemaPage(schema.Pagination, page) {
+			break
+		}
+	}
+
+	if len(errs) != 0 {
+		return output, errs
+	}
+
+	return output, nil
+}
+
+func (b *Bot) MustExtractWithSchema(schema *ExtractSchema) []map[string]any {
+	items, err := b.ExtractWithSchema(schema)
+	b.pie(err)
+
+	return items
+}
+
+// nextSchemaPage clicks the next button, it returns false when there's no more page.
+func (b *Bot) nextSchemaPage(p *SchemaPagination, page int) bool {
+	if p == nil || p.Next == "" || (p.MaxPages != 0 && page >= p.MaxPages) {
+		return false
+	}
+
+	next, err := b.Elem(p.Next, WithTimeout(b.shortTimeout.Seconds()))
+	if err != nil {
+		b.logger.Debug("no next page", zap.Int("page", page), zap.Error(err))
+		return false
+	}
+
+	if isDisabledElem(next) {
+		b.logger.Debug("next page is disabled", zap.Int("page", page))
+		return false
+	}
+
+	if err := b.ClickElem(next); err != nil {
+		b.logger.Warn("cannot click next page", zap.Int("page", page), zap.Error(err))
+		return false
+	}
+
+	if err := b.page.Timeout(b.mediumTimeout).WaitStable(b.pt1s); err != nil {
+		b.logger.Warn("next page is not stable", zap.Int("page", page), zap.Error(err))
+	}
+
+	return true
+}
+
+// schemaPageSign identifies a page by its url and first item.
+func (b *Bot) schemaPageSign(items []*rod.Element) string {
+	sign := b.CurrentURL()
+
+	if len(items) != 0 {
+		if html, err := items[0].HTML(); err == nil {
+			sign += "\n" + html
+		}
+	}
+
+	return sign
+}
+
+// isDisabledElem reports whether elem is disabled by property or aria-disabled, like the next button of the last page.
+func isDisabledElem(elem *rod.Element) bool {
+	if disabled, err := elem.Disabled(); err == nil && disabled {
+		return true
+	}
+
+	v, err := elem.Attribute("aria-disabled")
+
+	return err == nil && v != nil && *v == "true"
+}
+
+// validateTextSelector checks the text of "css@@@text" is a valid regex, as matched by findElems.
+func validateTextSelector(selector string, caseInsensitive bool) error {
+	ts, err := parseTextSelector(selector)
+	if err != nil {
+		return err
+	}
+
+	_, err = ts.regexp(caseInsensitive)
+
+	return err
+}
+
+func (ex *extractor) schemaObject(elem *rod.Element, fields []*SchemaField, prefix string) map[string]any {
+	obj := make(map[string]any, len(fields))
+
+	for _, f := range fields {
+		name := prefix + f.Name
+
+		elems, err := findElems(elem, f.spec)
+		if err != nil {
+			ex.fail(name, f.Selector, err)
+			continue
+		}
+
+		if f.Multiple {
+			list := make([]any, 0, len(elems))
+
+			for i, el := range elems {
+				if v, ok := ex.schemaValue(el, f, fmt.Sprintf("%s[%d]", name, i)); ok {
+					list = append(list, v)
+				}
+			}
+
+			if len(list) == 0 && f.Required {
+				ex.fail(name, f.Selector, ErrRequiredField)
+			}
+
+			obj[f.Name] = list
+
+			continue
+		}
+
+		// the value is nil when it cannot be read.
+		if len(elems) != 0 {
+			obj[f.Name], _ = ex.schemaValue(elems[0], f, name)
+		} else {
+			obj[f.Name], _ = ex.schemaMissing(f, name)
+		}
+	}
+
+	return obj
+}
+
+// schemaValue reads the value of f from elem, it returns false if there's no value.
+func (ex *extractor) schemaValue(elem *rod.Element, f *SchemaField, name string) (any, bool) {
+	if len(f.Fields) != 0 {
+		return ex.schemaObject(elem, f.Fields, name+"."), true
+	}
+
+	raw, err := readElemValue(elem, f.spec)
+	if err != nil {
+		ex.fail(name, f.Selector, err)
+		return nil, false
+	}
+
+	if f.reg != nil {
+		raw = regexValue(f.reg, raw)
+	}
+
+	if raw == "" {
+		return ex.schemaMissing(f, name)
+	}
+
+	v, err := transformValue(raw, f)
+	if err != nil {
+		ex.fail(name, f.Selector, err)
+		return nil, false
+	}
+
+	return v, true
+}
+
+func (ex *extractor) schemaMissing(f *SchemaField, name string) (any, bool) {
+	switch {
+	case f.spec.hasDef:
+		v, err := transformValue(f.spec.def, f)
+		if err != nil {
+			ex.fail(name, f.Selector, err)
+			return nil, false
+		}
+
+		return v, true
+	case f.Required:
+		ex.fail(name, f.Selector, ErrRequiredField)
+	}
+
+	return nil, false
+}
+
+// regexValue returns the first group of the match if any, else the whole match.
+func regexValue(reg *regexp.Regexp, raw string) string {
+	match := reg.FindStringSubmatch(raw)
+
+	switch len(match) {
+	case 0:
+		return ""
+	case 1:
+		return match[0]
+	default:
+		return match[1]
+	}
+}
+
+// transformValue converts raw by f.Transform, conversions are the same as Extract.
+func transformValue(raw string, f *SchemaField) (any, error) {
+	var v any
+
+	switch f.Transform {
+	case TransformInt:
+		v = new(int)
+	case TransformFloat:
+		v = new(float64)
+	case TransformBool:
+		v = new(bool)
+	case TransformTime:
+		v = new(time.Time)
+	case TransformLower:
+		return strings.ToLower(raw), nil
+	case TransformUpper:
+		return strings.ToUpper(raw), nil
+	default:
+		return raw, nil
+	}
+
+	rv := reflect.ValueOf(v).Elem()
+	if err := setExtractValue(rv, raw, f.spec); err != nil {
+		return nil, err
+	}
+
+	return rv.Interface(), nil
+}
@@ -0,0 +1,151 @@
+package wee
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coghost/wee/fixtures"
+	"github.com/stretchr/testify/suite"
+)
+
+const _jobsSchema = `
+name: jobs
+root: div.job
+popovers: ['#cookie-banner button']
+fields:
+  - name: title
+    selector: a.header
+    required: true
+  - name: id
+    selector: a.header
+    attr: href
+    regex: '/jobs/(\d+)'
+    transform: int
+  - name: salary
+    selector: span@@@Salary
+    transform: float
+    default: 0
+  - name: company
+    selector: div.company
+    fields:
+      - name: name
+        selector: .name
+        transform: upper
+      - name: city
+        selector: .city
+  - name: tags
+    selector: ul.tags li
+    multiple: true
+pagination:
+  next: a.next
+  max_pages: 3
+`
+
+type ExtractSchemaSuite struct {
+	suite.Suite
+}
+
+func TestExtractSchema(t *testing.T) {
+	suite.Run(t, new(ExtractSchemaSuite))
+}
+
+func (s *ExtractSchemaSuite) TestParse() {
+	schema, err := ParseExtractSchema([]byte(_jobsSchema), ConfigFormatYAML)
+	s.Require().NoError(err)
+	s.Equal("div.job", schema.Root)
+	s.Len(schema.Fields, 5)
+	s.Equal(&SchemaPagination{Next: "a.next", MaxPages: 3}, schema.Pagination)
+	s.Equal("0", schema.Fields[2].spec.def)
+	s.Equal("city", schema.Fields[3].Fields[1].Name)
+
+	schema, err = ParseExtractSchema([]byte(`{"root": "li", "fields": [{"name": "n", "transform": "int", "default": 1}]}`), ConfigFormatJSON)
+	s.Require().NoError(err)
+	s.Equal("1", schema.Fields[0].spec.def)
+
+	_, err = ParseExtractSchema([]byte("root: li\nfields: [{name: n, selecter: b}]"), ConfigFormatYAML)
+	s.ErrorIs(err, ErrInvalidSchema, "unknown key")
+
+	_, err = ParseExtractSchema([]byte(`fields: [{name: a}, {name: a, transform: date}, {name: b, regex: "("}, {name: c, selector: "span@@@("}]`), ConfigFormatYAML)
+	s.ErrorIs(err, ErrInvalidSchema)
+	s.ErrorContains(err, "root: must not be empty")
+	s.ErrorContains(err, `fields[1].name: duplicated "a"`)
+	s.ErrorContains(err, `fields[1].transform: unknown value "date"`)
+	s.ErrorContains(err, "fields[2].regex")
+	s.ErrorContains(err, "fields[3].selector")
+
+	_, err = ParseExtractSchema(nil, "toml")
+	s.ErrorIs(err, ErrUnknownConfigFormat)
+}
+
+func (s *ExtractSchemaSuite) TestTransform() {
+	f := &SchemaField{spec: &extractSpec{}}
+
+	tests := []struct {
+		transform string
+		raw       string
+		want      any
+	}{
+		{"", "Go Developer", "Go Developer"},
+		{TransformInt, "1 024 jobs", 1024},
+		{TransformFloat, "45 000.50 kr", 45000.5},
+		{TransformBool, "true", true},
+		{TransformLower, "ACME", "acme"},
+		{TransformUpper, "acme", "ACME"},
+		{TransformTime, "2024-05-01T00:00:00Z", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		f.Transform = tt.transform
+		v, err := transformValue(tt.raw, f)
+		s.Require().NoError(err, tt.transform)
+		s.Equal(tt.want, v, tt.transform)
+	}
+
+	f.Transform = TransformBool
+	_, err := transformValue("yes", f)
+	s.Error(err)
+}
+
+func (s *ExtractSchemaSuite) TestBot() {
+	ts := fixtures.NewTestServer()
+	defer ts.Close()
+
+	bot := NewBotHeadless()
+	defer bot.Cleanup()
+
+	schema, err := ParseExtractSchema([]byte(_jobsSchema), ConfigFormatYAML)
+	s.Require().NoError(err)
+
+	schema.URL = ts.URL + "/jobs"
+
+	items, err := bot.ExtractWithSchema(schema)
+
+	var errs ExtractErrors
+	s.Require().ErrorAs(err, &errs)
+	s.Len(errs, 1)
+	s.Equal(2, errs[0].Item)
+	s.Equal("title", errs[0].Field)
+	s.ErrorIs(errs[0], ErrRequiredField)
+
+	s.Require().Len(items, 4, "3 items of page 1 and 1 of page 2, its next link is disabled")
+
+	s.Equal(map[string]any{
+		"title":   "Go Developer",
+		"id":      1,
+		"salary":  45000.5,
+		"company": map[string]any{"name": "ACME", "city": "Stockholm"},
+		"tags":    []any{"go", "remote"},
+	}, items[0])
+
+	s.Equal(map[string]any{
+		"title":   "Scraper",
+		"id":      2,
+		"salary":  float64(0),
+		"company": map[string]any{"name": "WEE", "city": nil},
+		"tags":    []any{},
+	}, items[1])
+
+	s.Nil(items[2]["title"])
+	s.Equal("Tester", items[3]["title"])
+	s.Equal(30000.0, items[3]["salary"])
+}
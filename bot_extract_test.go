@@ -0,0 +1,186 @@
+package wee
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/coghost/wee/fixtures"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExtractSuite struct {
+	suite.Suite
+}
+
+func TestExtract(t *testing.T) {
+	suite.Run(t, new(ExtractSuite))
+}
+
+func (s *ExtractSuite) TestParseExtractTag() {
+	spec, err := parseExtractTag("css=a[href='/x'];attr=href;default=n/a;required;keep=,")
+	s.Require().NoError(err)
+	s.Equal(&extractSpec{
+		css: "a[href='/x']", attr: "href", def: "n/a", hasDef: true, required: true, keep: ",", hasKeep: true,
+	}, spec)
+
+	spec, err = parseExtractTag("css=span@@@---@@@salary;caseInsensitive")
+	s.Require().NoError(err)
+	s.Equal(&extractSpec{css: "span@@@---@@@salary", caseInsensitive: true}, spec)
+
+	_, err = parseExtractTag("css=a;selector=b")
+	s.ErrorIs(err, ErrInvalidExtractTag)
+}
+
+func (s *ExtractSuite) TestTextSelector() {
+	_, err := parseTextSelector("span")
+	s.ErrorIs(err, ErrInvalidByTextFormat)
+
+	partial, err := parseTextSelector("span@@@Sal.ry")
+	s.Require().NoError(err)
+	s.Equal("span", partial.css)
+	s.Equal("Sal.ry", partial.jsRegex(true), "case insensitive is only for exact match, like ElemByText")
+
+	reg, err := partial.regexp(true)
+	s.Require().NoError(err)
+	s.True(reg.MatchString("Salary: 45 000 kr"))
+	s.False(reg.MatchString("salary: 45 000 kr"))
+
+	// any three parts selector is an exact match.
+	for _, sel := range []string{"span@@@---@@@Salary", "span@@@@@@Salary", "span@@@exact@@@Salary"} {
+		exact, err := parseTextSelector(sel)
+		s.Require().NoError(err)
+		s.Equal("/^Salary$/i", exact.jsRegex(true), sel)
+
+		reg, err := exact.regexp(true)
+		s.Require().NoError(err)
+		s.True(reg.MatchString("salary"), sel)
+		s.False(reg.MatchString("Salary: 45 000 kr"), sel)
+	}
+}
+
+func (s *ExtractSuite) TestSetExtractValue() {
+	var v struct {
+		I  int
+		U  uint8
+		F  float64
+		B  bool
+		T  time.Time
+		Bs []byte
+	}
+
+	rv := reflect.ValueOf(&v).Elem()
+	spec := &extractSpec{}
+
+	s.NoError(setExtractValue(rv.Field(0), "-1 234 kr", spec))
+	s.Equal(-1234, v.I)
+
+	s.NoError(setExtractValue(rv.Field(1), "12 items", spec))
+	s.Equal(uint8(12), v.U)
+	s.Error(setExtractValue(rv.Field(1), "300", spec), "overflow")
+
+	s.ErrorIs(setExtractValue(rv.Field(0), "12.50 kr", spec), strconv.ErrSyntax, "decimal part is refused")
+
+	s.NoError(setExtractValue(rv.Field(2), "$1,234.50", spec))
+	s.InDelta(1234.5, v.F, 0.001)
+
+	s.NoError(setExtractValue(rv.Field(2), "1 234,50 kr", &extractSpec{keep: ",", hasKeep: true}))
+	s.InDelta(1234.5, v.F, 0.001)
+
+	s.NotPanics(func() { _ = setExtractValue(rv.Field(2), "1]2", &extractSpec{keep: `\]`, hasKeep: true}) })
+
+	s.NoError(setExtractValue(rv.Field(3), "true", spec))
+	s.True(v.B)
+
+	s.NoError(setExtractValue(rv.Field(4), "2024-05-01", &extractSpec{layout: time.DateOnly}))
+	s.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), v.T)
+	s.Error(setExtractValue(rv.Field(4), "2024-05-01", spec), "RFC3339 by default")
+
+	s.NoError(setExtractValue(rv.Field(5), "raw", spec))
+	s.Equal([]byte("raw"), v.Bs)
+}
+
+func (s *ExtractSuite) TestErrors() {
+	err := error(ExtractErrors{
+		{Item: 0, Field: "Posted", Selector: "time", Err: errors.New("bad time")},
+		{Item: 2, Field: "Title", Selector: "a.header", Err: ErrRequiredField},
+	})
+
+	s.ErrorIs(err, ErrRequiredField)
+
+	var fe *FieldError
+	s.Require().ErrorAs(err, &fe)
+	s.Equal("Posted", fe.Field)
+	s.Equal("item 0: Posted (time): bad time\nitem 2: Title (a.header): required field not found", err.Error())
+}
+
+type extractJob struct {
+	Title   string `wee:"css=a.header;required"`
+	URL     string `wee:"css=a.header;prop=href"`
+	Path    string `wee:"css=a.header;attr=href"`
+	Company struct {
+		Name string `wee:"css=.name"`
+		City string `wee:"css=.city;default=Remote"`
+	} `wee:"css=div.company"`
+	Salary float64    `wee:"css=span@@@Salary"`
+	Posted *time.Time `wee:"css=time;attr=datetime;layout=2006-01-02"`
+	Tags   []string   `wee:"css=ul.tags li"`
+	Remote bool       `wee:"css=span.remote;default=false"`
+	Note   string
+}
+
+func (s *ExtractSuite) TestBot() {
+	ts := fixtures.NewTestServer()
+	defer ts.Close()
+
+	bot := NewBotHeadless()
+	defer bot.Cleanup()
+
+	bot.MustOpen(ts.URL + "/jobs")
+
+	var jobs []*extractJob
+
+	err := bot.Extract("div.job", &jobs)
+
+	var errs ExtractErrors
+	s.Require().ErrorAs(err, &errs)
+	s.Len(errs, 2)
+	s.Equal(1, errs[0].Item)
+	s.Equal("Posted", errs[0].Field)
+	s.Equal(2, errs[1].Item)
+	s.ErrorIs(errs[1], ErrRequiredField)
+
+	s.Require().Len(jobs, 3)
+
+	posted := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	first := jobs[0]
+	s.Equal("Go Developer", first.Title)
+	s.Equal(ts.URL+"/jobs/1", first.URL)
+	s.Equal("/jobs/1", first.Path)
+	s.Equal("Acme", first.Company.Name)
+	s.Equal("Stockholm", first.Company.City)
+	s.InDelta(45000.5, first.Salary, 0.001)
+	s.Equal(&posted, first.Posted)
+	s.Equal([]string{"go", "remote"}, first.Tags)
+	s.True(first.Remote)
+
+	second := jobs[1]
+	s.Equal("Remote", second.Company.City)
+	s.Nil(second.Posted)
+	s.Empty(second.Tags)
+	s.False(second.Remote)
+
+	s.Equal("Ghost", jobs[2].Company.Name)
+
+	var summary struct {
+		Total int `wee:"css=#total b"`
+	}
+
+	s.Require().NoError(bot.Extract("", &summary))
+	s.Equal(1024, summary.Total)
+
+	s.ErrorIs(bot.Extract("div.job", jobs), ErrInvalidExtractTarget)
+}
@@ -0,0 +1,403 @@
+package wee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/gookit/goutil/fsutil"
+	"go.uber.org/zap"
+)
+
+// DefaultHARBodyLimit is the max size of a response body kept in HAR.
+const DefaultHARBodyLimit = 1 << 20
+
+var (
+	ErrHARStarted    = errors.New("har recording already started")
+	ErrHARNotStarted = errors.New("har recording not started")
+)
+
+// _harBodyOmitted starts the content comment of a response recorded without its body.
+const _harBodyOmitted = "body omitted"
+
+type HAROptions struct {
+	bodyLimit int
+}
+
+type HAROptionFunc func(o *HAROptions)
+
+func bindHAROptions(opt *HAROptions, opts ...HAROptionFunc) {
+	for _, f := range opts {
+		f(opt)
+	}
+}
+
+// WithHARBodyLimit keeps response bodies up to n bytes, bigger ones are left out with a comment.
+// n <= 0 records no body.
+func WithHARBodyLimit(n int) HAROptionFunc {
+	return func(o *HAROptions) {
+		o.bodyLimit = n
+	}
+}
+
+// harRecorder builds HAR entries from the network events of one page.
+// Events are handled in one goroutine, the lock protects entries from StopHAR.
+type harRecorder struct {
+	mu      sync.Mutex
+	opt     *HAROptions
+	page    *rod.Page
+	cancel  context.CancelFunc
+	done    chan struct{}
+	logger  *zap.Logger
+	entries []*harPending
+	pending map[proto.NetworkRequestID]*harPending
+}
+
+type harPending struct {
+	entry     *HAREntry
+	started   proto.MonotonicTime
+	timing    *proto.NetworkResourceTiming
+	completed bool
+}
+
+// StartHAR starts recording every request and response of the bot's page into a HAR,
+// until StopHAR is called.
+//
+// It listens to the devtools network events, nothing is intercepted, so traffic is not altered.
+// Redirects are recorded as separate entries with redirectURL set.
+// Requests of pages opened after StartHAR (e.g. new tabs) are not recorded.
+//
+// Usage:
+//
+//	bot.MustStartHAR()
+//	bot.MustOpen(uri)
+//	_, err := bot.StopHAR("/tmp/session.har")
+func (b *Bot) StartHAR(opts ...HAROptionFunc) error {
+	if b.har.recorder() != nil {
+		return ErrHARStarted
+	}
+
+	opt := &HAROptions{bodyLimit: DefaultHARBodyLimit}
+	bindHAROptions(opt, opts...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	rec := &harRecorder{
+		opt:     opt,
+		page:    b.page.Context(ctx),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		logger:  b.logger,
+		pending: make(map[proto.NetworkRequestID]*harPending),
+	}
+
+	wait := rec.page.EachEvent(
+		rec.onRequest,
+		rec.onResponse,
+		rec.onFinished,
+		rec.onFailed,
+	)
+
+	go func() {
+		defer close(rec.done)
+		wait()
+	}()
+
+	b.har.set(rec)
+
+	return nil
+}
+
+func (b *Bot) MustStartHAR(opts ...HAROptionFunc) {
+	b.pie(b.StartHAR(opts...))
+}
+
+// StopHAR stops recording, and saves the HAR to path if path is not empty.
+// Requests still in flight are kept with a "pending" comment.
+func (b *Bot) StopHAR(path string) (*HAR, error) {
+	rec := b.har.recorder()
+	if rec == nil {
+		return nil, ErrHARNotStarted
+	}
+
+	b.har.set(nil)
+
+	rec.cancel()
+	<-rec.done
+
+	har := rec.build()
+
+	if path == "" {
+		return har, nil
+	}
+
+	if err := fsutil.MkParentDir(path); err != nil {
+		return nil, err
+	}
+
+	if err := har.Save(path); err != nil {
+		return nil, fmt.Errorf("cannot save har: %w", err)
+	}
+
+	return har, nil
+}
+
+// harState holds the running recorder, shared by WithContext copies of the bot.
+type harState struct {
+	mu  sync.Mutex
+	rec *harRecorder
+}
+
+func (s *harState) recorder() *harRecorder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rec
+}
+
+func (s *harState) set(rec *harRecorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rec = rec
+}
+
+func (r *harRecorder) onRequest(e *proto.NetworkRequestWillBeSent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// a redirect reuses the request id, finish the previous hop with the redirect response.
+	if prev, ok := r.pending[e.RequestID]; ok && e.RedirectResponse != nil {
+		r.setResponse(prev, e.RedirectResponse, string(e.Type))
+		prev.entry.Response.RedirectURL = e.Request.URL
+		r.complete(prev, e.Timestamp, 0)
+	}
+
+	headers := toHTTPHeader(e.Request.Headers)
+
+	entry := &HAREntry{
+		StartedDateTime: e.WallTime.Time().UTC().Format(time.RFC3339Nano),
+		Request: &HARRequest{
+			Method:      e.Request.Method,
+			URL:         e.Request.URL + e.Request.URLFragment,
+			Cookies:     harCookies(headers, "Cookie"),
+			Headers:     harNameValues(headers),
+			QueryString: harQueryString(e.Request.URL),
+			HeadersSize: -1,
+			BodySize:    len(e.Request.PostData),
+		},
+		Timings:      &HARTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1},
+		ResourceType: string(e.Type),
+	}
+
+	if e.Request.HasPostData || e.Request.PostData != "" {
+		entry.Request.PostData = &HARPostData{MimeType: headers.Get("Content-Type"), Text: e.Request.PostData}
+	}
+
+	p := &harPending{entry: entry, started: e.Timestamp}
+	r.pending[e.RequestID] = p
+	r.entries = append(r.entries, p)
+}
+
+func (r *harRecorder) onResponse(e *proto.NetworkResponseReceived) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if p, ok := r.pending[e.RequestID]; ok {
+		r.setResponse(p, e.Response, string(e.Type))
+	}
+}
+
+func (r *harRecorder) onFinished(e *proto.NetworkLoadingFinished) {
+	r.mu.Lock()
+	p, ok := r.pending[e.RequestID]
+	r.mu.Unlock()
+
+	if !ok || p.entry.Response == nil {
+		return
+	}
+
+	// read body out of the lock, it's a devtools call.
+	content := r.content(e.RequestID, p.entry.Response.Content)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p.entry.Response.Content = content
+	r.complete(p, e.Timestamp, int(e.EncodedDataLength))
+	delete(r.pending, e.RequestID)
+}
+
+func (r *harRecorder) onFailed(e *proto.NetworkLoadingFailed) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.pending[e.RequestID]
+	if !ok {
+		return
+	}
+
+	p.entry.Comment = e.ErrorText
+	r.complete(p, e.Timestamp, 0)
+	delete(r.pending, e.RequestID)
+}
+
+func (r *harRecorder) setResponse(p *harPending, resp *proto.NetworkResponse, resourceType string) {
+	headers := toHTTPHeader(resp.Headers)
+
+	// request headers from response are the ones actually sent, e.g. with cookies.
+	if len(resp.RequestHeaders) != 0 {
+		reqHeaders := toHTTPHeader(resp.RequestHeaders)
+		p.entry.Request.Headers = harNameValues(reqHeaders)
+		p.entry.Request.Cookies = harCookies(reqHeaders, "Cookie")
+	}
+
+	version := harHTTPVersion(resp.Protocol)
+	p.entry.Request.HTTPVersion = version
+	p.entry.ServerIPAddress = resp.RemoteIPAddress
+	p.entry.ResourceType = StrAorB(resourceType, p.entry.ResourceType)
+	p.timing = resp.Timing
+
+	p.entry.Response = &HARResponse{
+		Status:      resp.Status,
+		StatusText:  resp.StatusText,
+		HTTPVersion: version,
+		Cookies:     harCookies(headers, "Set-Cookie"),
+		Headers:     harNameValues(headers),
+		Content:     &HARContent{MimeType: resp.MIMEType},
+		RedirectURL: headers.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+}
+
+// content reads the response body into a copy of content.
+// A body left out is marked by a comment starting with _harBodyOmitted, so replay won't serve it as empty.
+func (r *harRecorder) content(reqID proto.NetworkRequestID, content *HARContent) *HARContent {
+	c := *content
+
+	if r.opt.bodyLimit <= 0 {
+		c.Comment = _harBodyOmitted + ": body limit is 0"
+		return &c
+	}
+
+	res, err := proto.NetworkGetResponseBody{RequestID: reqID}.Call(r.page)
+	if err != nil {
+		// e.g. preflight requests have no body.
+		r.logger.Debug("cannot get response body for har", zap.String("request", string(reqID)), zap.Error(err))
+		c.Comment = fmt.Sprintf("%s: %v", _harBodyOmitted, err)
+
+		return &c
+	}
+
+	size := len(res.Body)
+	if res.Base64Encoded {
+		size = len(res.Body)*3/4 - strings.Count(res.Body[max(0, len(res.Body)-2):], "=")
+	}
+
+	c.Size = size
+
+	switch {
+	case size > r.opt.bodyLimit:
+		c.Comment = fmt.Sprintf("%s: %d bytes over limit %d", _harBodyOmitted, size, r.opt.bodyLimit)
+	case res.Base64Encoded:
+		c.Text, c.Encoding = res.Body, "base64"
+	default:
+		c.Text = res.Body
+	}
+
+	return &c
+}
+
+// complete calculates timings when the request is done at ts.
+func (r *harRecorder) complete(p *harPending, ts proto.MonotonicTime, encodedSize int) {
+	if p.completed {
+		return
+	}
+
+	p.completed = true
+
+	if p.entry.Response != nil && encodedSize > 0 {
+		p.entry.Response.BodySize = encodedSize
+	}
+
+	total := float64(ts-p.started) * float64(time.Second/time.Millisecond)
+
+	t := p.entry.Timings
+	if tm := p.timing; tm != nil {
+		t.Blocked = firstNonNegative(tm.DNSStart, tm.ConnectStart, tm.SendStart)
+		t.DNS = timingSpan(tm.DNSStart, tm.DNSEnd)
+		t.Connect = timingSpan(tm.ConnectStart, tm.ConnectEnd)
+		t.SSL = timingSpan(tm.SslStart, tm.SslEnd)
+		// send, wait and receive are required to be non-negative.
+		t.Send = max(0, timingSpan(tm.SendStart, tm.SendEnd))
+		t.Wait = max(0, timingSpan(tm.SendEnd, tm.ReceiveHeadersEnd))
+		// requestTime is the same monotonic clock of ts, in seconds.
+		t.Receive = max(0, (float64(ts)-tm.RequestTime)*float64(time.Second/time.Millisecond)-tm.ReceiveHeadersEnd)
+	} else {
+		t.Send, t.Wait, t.Receive = 0, max(0, total), 0
+	}
+
+	p.entry.Time = 0
+	for _, v := range []float64{t.Blocked, t.DNS, t.Connect, t.Send, t.Wait, t.Receive} {
+		if v > 0 {
+			p.entry.Time += v
+		}
+	}
+}
+
+func (r *harRecorder) build() *HAR {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	har := newHAR()
+
+	for _, p := range r.entries {
+		entry := p.entry
+
+		if !p.completed {
+			entry.Comment = StrAorB(entry.Comment, "pending")
+			if entry.Timings.Send < 0 {
+				entry.Timings.Send = 0
+			}
+		}
+
+		if entry.Response == nil {
+			entry.Response = &HARResponse{
+				Cookies:     []*HARNameValue{},
+				Headers:     []*HARNameValue{},
+				Content:     &HARContent{Size: 0},
+				HeadersSize: -1,
+				BodySize:    -1,
+			}
+		}
+
+		har.Log.Entries = append(har.Log.Entries, entry)
+	}
+
+	return har
+}
+
+func firstNonNegative(values ...float64) float64 {
+	for _, v := range values {
+		if v >= 0 {
+			return v
+		}
+	}
+
+	return -1
+}
+
+// timingSpan returns end-start in milliseconds, -1 if not applicable.
+func timingSpan(start, end float64) float64 {
+	if start < 0 || end < 0 {
+		return -1
+	}
+
+	return end - start
+}
@@ -0,0 +1,243 @@
+package wee
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+)
+
+// HARReplayPriority is the priority of the replay rule, lower than the default one,
+// so rules added by AddHijackRule, Hijack etc. still run before the replay.
+const HARReplayPriority = -100
+
+// HARUnmatchedPolicy decides what happens to a request not found in the replayed HAR.
+type HARUnmatchedPolicy int
+
+const (
+	// HARUnmatchedFail fails the request as if the network is disconnected, nothing goes out.
+	HARUnmatchedFail HARUnmatchedPolicy = iota
+	// HARUnmatchedPassThrough sends the request to the network.
+	HARUnmatchedPassThrough
+)
+
+type HARReplayOptions struct {
+	unmatched HARUnmatchedPolicy
+}
+
+type HARReplayOptionFunc func(o *HARReplayOptions)
+
+func bindHARReplayOptions(opt *HARReplayOptions, opts ...HARReplayOptionFunc) {
+	for _, f := range opts {
+		f(opt)
+	}
+}
+
+// WithHARUnmatched sets the policy of unmatched requests, default is HARUnmatchedFail.
+func WithHARUnmatched(policy HARUnmatchedPolicy) HARReplayOptionFunc {
+	return func(o *HARReplayOptions) {
+		o.unmatched = policy
+	}
+}
+
+// _fulfillSkippedHeaders are not fulfilled, the stored body is already decoded and sized by the browser.
+var _fulfillSkippedHeaders = map[string]bool{
+	"content-encoding":  true,
+	"content-length":    true,
+	"transfer-encoding": true,
+}
+
+// ReplayHAR serves responses from the HAR file at path, e.g. recorded by StartHAR/StopHAR or exported from devtools,
+// so a bot script can run against a frozen snapshot of a site without network.
+//
+// Requests are matched by method and url (fragment ignored), and by post data when more than one entry matches.
+// Entries of a repeated request are served in the recorded order, the last one is served again when all are used.
+// Recorded failures are replayed as failures, redirects are replayed and followed by the browser.
+//
+// Unmatched requests are failed by default, see WithHARUnmatched.
+// Entries recorded without body, e.g. over the limit of WithHARBodyLimit, are unmatched, not served empty.
+// The replay is a rule of the bot's router with HARReplayPriority, remove it with the returned handle or StopHijack.
+//
+// Usage:
+//
+//	h, err := bot.ReplayHAR("testdata/site.har")
+//	defer h.Remove()
+//
+//	bot.MustOpen("https://example.com/list")
+func (b *Bot) ReplayHAR(path string, opts ...HARReplayOptionFunc) (*HijackRuleHandle, error) {
+	har, err := LoadHAR(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot replay har: %w", err)
+	}
+
+	opt := &HARReplayOptions{unmatched: HARUnmatchedFail}
+	bindHARReplayOptions(opt, opts...)
+
+	rp := newHARReplayer(har, opt, b.logger)
+
+	return b.AddHijackRule(HijackRule{
+		Priority: HARReplayPriority,
+		Handler:  rp.serve,
+	})
+}
+
+func (b *Bot) MustReplayHAR(path string, opts ...HARReplayOptionFunc) *HijackRuleHandle {
+	h, err := b.ReplayHAR(path, opts...)
+	b.pie(err)
+
+	return h
+}
+
+// harReplayer finds the recorded entry of a request, requests are handled concurrently.
+type harReplayer struct {
+	mu      sync.Mutex
+	opt     *HARReplayOptions
+	logger  *zap.Logger
+	entries map[string][]*HAREntry
+	served  map[string]int
+}
+
+func newHARReplayer(har *HAR, opt *HARReplayOptions, logger *zap.Logger) *harReplayer {
+	rp := &harReplayer{
+		opt:     opt,
+		logger:  logger,
+		entries: make(map[string][]*HAREntry),
+		served:  make(map[string]int),
+	}
+
+	for _, e := range har.Log.Entries {
+		if e.Request == nil {
+			continue
+		}
+
+		key := harReplayKey(e.Request.Method, e.Request.URL)
+		rp.entries[key] = append(rp.entries[key], e)
+	}
+
+	return rp
+}
+
+func (rp *harReplayer) serve(ctx *rod.Hijack) {
+	uri := ctx.Request.URL().String()
+
+	entry := rp.lookup(ctx.Request.Method(), uri, ctx.Request.Body())
+	if entry == nil {
+		if rp.opt.unmatched == HARUnmatchedPassThrough {
+			ctx.Skip = true
+			return
+		}
+
+		rp.logger.Debug("no har entry, request failed", zap.String("method", ctx.Request.Method()), zap.String("url", uri))
+		ctx.Response.Fail(proto.NetworkErrorReasonInternetDisconnected)
+
+		return
+	}
+
+	if entry.Response == nil || entry.Response.Status == 0 {
+		ctx.Response.Fail(proto.NetworkErrorReasonFailed)
+		return
+	}
+
+	payload := ctx.Response.Payload()
+	payload.ResponseCode = entry.Response.Status
+	payload.ResponsePhrase = entry.Response.StatusText
+	payload.ResponseHeaders = harReplayHeaders(entry.Response.Headers)
+
+	body, err := harContentBody(entry.Response.Content)
+	if err != nil {
+		rp.logger.Warn("cannot decode har content", zap.String("url", uri), zap.Error(err))
+	}
+
+	payload.Body = body
+}
+
+// lookup returns the next entry of the request, or nil if not recorded or its body is omitted.
+func (rp *harReplayer) lookup(method, uri, body string) *HAREntry {
+	key := harReplayKey(method, uri)
+
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	candidates := rp.entries[key]
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if len(candidates) > 1 {
+		var sameBody []*HAREntry
+
+		for _, e := range candidates {
+			if e.Request.PostData != nil && e.Request.PostData.Text == body {
+				sameBody = append(sameBody, e)
+			}
+		}
+
+		if len(sameBody) != 0 {
+			candidates = sameBody
+			key += "\n" + body
+		}
+	}
+
+	i := min(rp.served[key], len(candidates)-1)
+	rp.served[key]++
+
+	entry := candidates[i]
+	if harBodyOmitted(entry) {
+		rp.logger.Warn("har entry has no body, request is unmatched",
+			zap.String("method", method), zap.String("url", uri), zap.String("comment", entry.Response.Content.Comment))
+
+		return nil
+	}
+
+	return entry
+}
+
+// harBodyOmitted reports whether the response has a body not saved in the har,
+// either marked by the recorder or sized by other tools without text.
+func harBodyOmitted(e *HAREntry) bool {
+	if e.Response == nil || e.Response.Content == nil || e.Response.Content.Text != "" {
+		return false
+	}
+
+	c := e.Response.Content
+
+	return c.Size > 0 || strings.HasPrefix(c.Comment, _harBodyOmitted)
+}
+
+func harReplayKey(method, uri string) string {
+	uri, _, _ = strings.Cut(uri, "#")
+	return strings.ToUpper(method) + " " + uri
+}
+
+func harReplayHeaders(headers []*HARNameValue) []*proto.FetchHeaderEntry {
+	entries := []*proto.FetchHeaderEntry{}
+
+	for _, h := range headers {
+		name := strings.ToLower(h.Name)
+		// http/2 pseudo headers like ":status" are exported by some browsers.
+		if _fulfillSkippedHeaders[name] || strings.HasPrefix(name, ":") {
+			continue
+		}
+
+		entries = append(entries, &proto.FetchHeaderEntry{Name: http.CanonicalHeaderKey(h.Name), Value: h.Value})
+	}
+
+	return entries
+}
+
+func harContentBody(content *HARContent) ([]byte, error) {
+	if content == nil || content.Text == "" {
+		return []byte{}, nil
+	}
+
+	if content.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(content.Text)
+	}
+
+	return []byte(content.Text), nil
+}
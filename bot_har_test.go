@@ -0,0 +1,255 @@
+package wee
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coghost/wee/fixtures"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/suite"
+	"github.com/ysmood/gson"
+	"go.uber.org/zap"
+)
+
+type HARSuite struct {
+	suite.Suite
+}
+
+func TestHAR(t *testing.T) {
+	suite.Run(t, new(HARSuite))
+}
+
+func (s *HARSuite) newRecorder() *harRecorder {
+	return &harRecorder{
+		opt:     &HAROptions{bodyLimit: 0},
+		logger:  zap.NewNop(),
+		pending: make(map[proto.NetworkRequestID]*harPending),
+	}
+}
+
+func (s *HARSuite) TestRecordEvents() {
+	rec := s.newRecorder()
+
+	rec.onRequest(&proto.NetworkRequestWillBeSent{
+		RequestID: "1",
+		Request: &proto.NetworkRequest{
+			URL:      "http://example.com/login?next=%2Fhome",
+			Method:   "POST",
+			Headers:  proto.NetworkHeaders{"Content-Type": gson.New("application/x-www-form-urlencoded")},
+			PostData: "user=a",
+		},
+		Timestamp: 100,
+		WallTime:  1_700_000_000,
+		Type:      proto.NetworkResourceTypeDocument,
+	})
+
+	// redirected to /home
+	rec.onRequest(&proto.NetworkRequestWillBeSent{
+		RequestID: "1",
+		Request:   &proto.NetworkRequest{URL: "http://example.com/home", Method: "GET"},
+		Timestamp: 100.2,
+		WallTime:  1_700_000_000.2,
+		Type:      proto.NetworkResourceTypeDocument,
+		RedirectResponse: &proto.NetworkResponse{
+			Status:   302,
+			Headers:  proto.NetworkHeaders{"Set-Cookie": gson.New("sid=s001; Path=/"), "Location": gson.New("/home")},
+			Protocol: "http/1.1",
+		},
+	})
+
+	rec.onResponse(&proto.NetworkResponseReceived{
+		RequestID: "1",
+		Type:      proto.NetworkResourceTypeDocument,
+		Response: &proto.NetworkResponse{
+			Status:   200,
+			MIMEType: "text/html",
+			Protocol: "h2",
+			Timing: &proto.NetworkResourceTiming{
+				RequestTime: 100.2, DNSStart: -1, DNSEnd: -1, ConnectStart: -1, ConnectEnd: -1,
+				SslStart: -1, SslEnd: -1, SendStart: 1, SendEnd: 2, ReceiveHeadersEnd: 50,
+			},
+		},
+	})
+	rec.onFinished(&proto.NetworkLoadingFinished{RequestID: "1", Timestamp: 100.3, EncodedDataLength: 512})
+
+	rec.onRequest(&proto.NetworkRequestWillBeSent{
+		RequestID: "2", Request: &proto.NetworkRequest{URL: "http://example.com/ad.js", Method: "GET"}, Timestamp: 101,
+	})
+	rec.onFailed(&proto.NetworkLoadingFailed{RequestID: "2", Timestamp: 101.1, ErrorText: "net::ERR_BLOCKED_BY_CLIENT"})
+
+	rec.onRequest(&proto.NetworkRequestWillBeSent{
+		RequestID: "3", Request: &proto.NetworkRequest{URL: "http://example.com/slow", Method: "GET"}, Timestamp: 102,
+	})
+
+	entries := rec.build().Log.Entries
+	s.Require().Len(entries, 4)
+
+	login := entries[0]
+	s.Equal(302, login.Response.Status)
+	s.Equal("http://example.com/home", login.Response.RedirectURL)
+	s.Equal([]*HARNameValue{{Name: "sid", Value: "s001"}}, login.Response.Cookies)
+	s.Equal([]*HARNameValue{{Name: "next", Value: "/home"}}, login.Request.QueryString)
+	s.Equal(&HARPostData{MimeType: "application/x-www-form-urlencoded", Text: "user=a"}, login.Request.PostData)
+	s.Equal("HTTP/1.1", login.Response.HTTPVersion)
+	s.InDelta(200, login.Time, 0.01)
+
+	home := entries[1]
+	s.Equal(200, home.Response.Status)
+	s.Equal("HTTP/2", home.Response.HTTPVersion)
+	s.Equal(512, home.Response.BodySize)
+	s.Equal(&HARTimings{Blocked: 1, DNS: -1, Connect: -1, SSL: -1, Send: 1, Wait: 48, Receive: 50}, roundTimings(home.Timings))
+
+	s.Equal("net::ERR_BLOCKED_BY_CLIENT", entries[2].Comment)
+	s.Equal(0, entries[2].Response.Status)
+
+	s.Equal("pending", entries[3].Comment)
+	s.GreaterOrEqual(entries[3].Timings.Send, 0.0)
+}
+
+func (s *HARSuite) TestRecordBot() {
+	ts := fixtures.NewTestServer()
+	defer ts.Close()
+
+	bot := NewBotHeadless()
+	defer bot.Cleanup()
+
+	s.Require().NoError(bot.StartHAR())
+	s.ErrorIs(bot.StartHAR(), ErrHARStarted)
+
+	bot.MustOpen(ts.URL + "/xhr_test")
+	bot.page.MustWaitRequestIdle()
+
+	file := filepath.Join(s.T().TempDir(), "session.har")
+	_, err := bot.StopHAR(file)
+	s.Require().NoError(err)
+
+	_, err = bot.StopHAR(file)
+	s.ErrorIs(err, ErrHARNotStarted)
+
+	har, err := LoadHAR(file)
+	s.Require().NoError(err)
+	s.Equal("1.2", har.Log.Version)
+
+	var api *HAREntry
+
+	for _, e := range har.Log.Entries {
+		if strings.HasSuffix(e.Request.URL, "/api/data") {
+			api = e
+		}
+	}
+
+	s.Require().NotNil(api, "fetch of /api/data is recorded")
+	s.Equal(200, api.Response.Status)
+	s.Contains(api.Response.Content.Text, "XHR response")
+	s.Equal("application/json", api.Response.Content.MimeType)
+}
+
+func roundTimings(t *HARTimings) *HARTimings {
+	round := func(v float64) float64 {
+		return math.Round(v*1000) / 1000
+	}
+
+	return &HARTimings{
+		Blocked: round(t.Blocked), DNS: round(t.DNS), Connect: round(t.Connect), SSL: round(t.SSL),
+		Send: round(t.Send), Wait: round(t.Wait), Receive: round(t.Receive),
+	}
+}
+
+func (s *HARSuite) TestReplayLookup() {
+	har := newHAR()
+	har.Log.Entries = []*HAREntry{
+		{Request: &HARRequest{Method: "GET", URL: "http://example.com/poll#top"}, Response: &HARResponse{Status: 200, Content: &HARContent{Text: "1"}}},
+		{Request: &HARRequest{Method: "GET", URL: "http://example.com/poll"}, Response: &HARResponse{Status: 200, Content: &HARContent{Text: "2"}}},
+		{Request: &HARRequest{Method: "POST", URL: "http://example.com/q", PostData: &HARPostData{Text: "a"}}, Response: &HARResponse{Status: 200, Content: &HARContent{Text: "qa"}}},
+		{Request: &HARRequest{Method: "POST", URL: "http://example.com/q", PostData: &HARPostData{Text: "b"}}, Response: &HARResponse{Status: 200, Content: &HARContent{Text: "qb"}}},
+		{Request: &HARRequest{Method: "GET", URL: "http://example.com/big.mp4"}, Response: &HARResponse{Status: 200, Content: &HARContent{Size: 1 << 30, Comment: "over limit"}}},
+	}
+
+	rp := newHARReplayer(har, &HARReplayOptions{}, zap.NewNop())
+
+	text := func(e *HAREntry) string {
+		s.Require().NotNil(e)
+		return e.Response.Content.Text
+	}
+
+	s.Equal("1", text(rp.lookup("GET", "http://example.com/poll", "")))
+	s.Equal("2", text(rp.lookup("get", "http://example.com/poll#x", "")))
+	s.Equal("2", text(rp.lookup("GET", "http://example.com/poll", "")), "last entry is served again")
+
+	s.Equal("qb", text(rp.lookup("POST", "http://example.com/q", "b")))
+	s.Equal("qa", text(rp.lookup("POST", "http://example.com/q", "a")))
+
+	s.Nil(rp.lookup("GET", "http://example.com/q", ""))
+	s.Nil(rp.lookup("GET", "http://example.com/other", ""))
+	s.Nil(rp.lookup("GET", "http://example.com/big.mp4", ""), "body over limit is unmatched")
+
+	body, err := harContentBody(&HARContent{Text: "aGk=", Encoding: "base64"})
+	s.Require().NoError(err)
+	s.Equal("hi", string(body))
+
+	headers := harReplayHeaders([]*HARNameValue{
+		{Name: ":status", Value: "200"},
+		{Name: "content-encoding", Value: "gzip"},
+		{Name: "content-type", Value: "text/plain"},
+	})
+	s.Equal([]*proto.FetchHeaderEntry{{Name: "Content-Type", Value: "text/plain"}}, headers)
+}
+
+func (s *HARSuite) TestReplayWithoutBody() {
+	// recorded with WithHARBodyLimit(0).
+	rec := s.newRecorder()
+
+	rec.onRequest(&proto.NetworkRequestWillBeSent{
+		RequestID: "1", Request: &proto.NetworkRequest{URL: "http://example.com/api/data", Method: "GET"}, Timestamp: 100,
+	})
+	rec.onResponse(&proto.NetworkResponseReceived{
+		RequestID: "1",
+		Type:      proto.NetworkResourceTypeFetch,
+		Response:  &proto.NetworkResponse{Status: 200, MIMEType: "application/json"},
+	})
+	rec.onFinished(&proto.NetworkLoadingFinished{RequestID: "1", Timestamp: 100.1, EncodedDataLength: 128})
+
+	har := rec.build()
+	s.Require().Len(har.Log.Entries, 1)
+
+	content := har.Log.Entries[0].Response.Content
+	s.Empty(content.Text)
+	s.True(strings.HasPrefix(content.Comment, _harBodyOmitted))
+
+	rp := newHARReplayer(har, &HARReplayOptions{}, zap.NewNop())
+	s.Nil(rp.lookup("GET", "http://example.com/api/data", ""), "body not recorded is unmatched, not an empty 200")
+}
+
+func (s *HARSuite) TestReplayBot() {
+	ts := fixtures.NewTestServer()
+	uri := ts.URL + "/xhr_test"
+	file := filepath.Join(s.T().TempDir(), "site.har")
+
+	rec := NewBotHeadless()
+	rec.MustStartHAR()
+	rec.MustOpen(uri)
+	rec.page.MustWaitRequestIdle()
+	_, err := rec.StopHAR(file)
+	s.Require().NoError(err)
+	rec.Cleanup()
+
+	// no network from now on.
+	ts.Close()
+
+	bot := NewBotHeadless()
+	defer bot.Cleanup()
+
+	h := bot.MustReplayHAR(file)
+	defer h.Remove()
+
+	bot.MustOpen(uri)
+	s.Equal("XHR Test", bot.page.MustElement("h1").MustText())
+
+	got := bot.page.MustEval(`() => fetch('/api/data').then(r => r.json()).then(d => d.message)`).Str()
+	s.Equal("XHR response", got)
+
+	failed := bot.page.MustEval(`() => fetch('/not-recorded').then(() => false, () => true)`).Bool()
+	s.True(failed, "unmatched request fails by default")
+}
@@ -166,12 +166,15 @@ func (b *Bot) Highlight(elem *rod.Element, show, hide float64, style string, cou
 		script := fmt.Sprintf(`() => this.setAttribute("style", "%s");`, style)
 		_, _ = elem.Eval(script)
 
-		RandSleep(show-base, show+base)
+		err := b.randSleep(show-base, show+base)
 
 		script = fmt.Sprintf(`() => this.setAttribute("style", "%s");`, origStyle)
 		_, _ = elem.Eval(script)
 
-		RandSleep(hide-base, hide+base)
+		// the bot's context is done, stop pulsing.
+		if err != nil || b.randSleep(hide-base, hide+base) != nil {
+			break
+		}
 	}
 
 	cost := time.Since(start).Seconds()
@@ -224,7 +227,7 @@ func (b *Bot) MarkElems(timeout time.Duration, elems ...*rod.Element) {
 //	 transition: all 0.5s ease-in-out; animation-delay: 0.1s;`
 //
 // Notes:
-//   - This method is synchronous and will block for the duration of the timeout.
+//   - This method is synchronous and will block for the duration of the timeout, or until the bot's context is done.
 //   - The original style restoration is deferred, ensuring it occurs even if there's a panic.
 //   - Currently, the original style capture is commented out, which means the element
 //     will revert to having no inline style after the timeout.
@@ -256,5 +259,11 @@ func (b *Bot) MarkElem(timeout time.Duration, elem *rod.Element, style string) {
 	script := fmt.Sprintf(`() => this.setAttribute("style", "%s");`, style)
 	_, _ = elem.Eval(script)
 
-	time.Sleep(timeout)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-b.Context().Done():
+	}
 }
@@ -65,6 +65,7 @@ import (
 //     of the web page being automated.
 //
 // Related Methods:
+//   - Bot.AddHijackRule: Adds a rule with priority to the bot's router.
 //   - Bot.HijackAny: Hijacks requests matching any of the specified resource strings.
 //   - Bot.Hijack: Hijacks requests matching specified patterns and resource types.
 //
@@ -116,35 +117,33 @@ type HijackHandler func(*rod.Hijack)
 //	)
 //
 // Notes:
-//   - This method starts a goroutine, so it returns immediately.
+//   - The rule is added to the bot's router (see AddHijackRule), remove it with the returned handle.
 //   - Be cautious with resource-intensive operations in the handler to avoid performance issues.
 //   - Modifying responses can affect page functionality; use with care.
 //
 // See Also:
 //   - Bot.Hijack: For more specific request targeting by pattern and resource type.
 //   - rod.Hijack documentation for available methods on the Hijack object.
-func (b *Bot) HijackAny(resources []string, handler HijackHandler, continueRequest bool) {
+func (b *Bot) HijackAny(resources []string, handler HijackHandler, continueRequest bool) *HijackRuleHandle {
 	if len(resources) == 0 {
-		return
+		return nil
 	}
 
-	router := b.browser.HijackRequests()
-
-	router.MustAdd("*", func(ctx *rod.Hijack) {
-		for _, res := range resources {
-			if strings.Contains(ctx.Request.URL().String(), res) {
-				handler(ctx)
-
-				if !continueRequest {
-					return
+	handle, err := b.AddHijackRule(HijackRule{
+		Match: func(ctx *rod.Hijack) bool {
+			for _, res := range resources {
+				if strings.Contains(ctx.Request.URL().String(), res) {
+					return true
 				}
 			}
-		}
 
-		ctx.ContinueRequest(&proto.FetchContinueRequest{})
+			return false
+		},
+		Handler: continueAfter(handler, continueRequest),
 	})
+	b.pie(err)
 
-	go router.Run()
+	return handle
 }
 
 // Hijack intercepts network requests that match specified patterns and resource types.
@@ -175,7 +174,7 @@ func (b *Bot) HijackAny(resources []string, handler HijackHandler, continueReque
 // Implementation Details:
 //   - Uses rod's pattern matching for URLs, which supports glob patterns.
 //   - Only requests matching both pattern and resource type are intercepted.
-//   - Non-matching requests (by type) are automatically continued.
+//   - Non-matching requests are passed to other rules of the bot's router, or continued.
 //
 // Use Cases:
 //   - Intercepting specific types of requests (e.g., only XHR or Image requests).
@@ -196,7 +195,8 @@ func (b *Bot) HijackAny(resources []string, handler HijackHandler, continueReque
 //	)
 //
 // Notes:
-//   - This method starts a goroutine, so it returns immediately.
+//   - One rule per pattern is added to the bot's router (see AddHijackRule),
+//     the returned handle removes all of them.
 //   - Be cautious with resource-intensive operations in the handler to avoid performance issues.
 //   - Modifying responses can affect page functionality; use with care.
 //
@@ -204,28 +204,37 @@ func (b *Bot) HijackAny(resources []string, handler HijackHandler, continueReque
 //   - Bot.HijackAny: For intercepting requests based on URL content without type restrictions.
 //   - proto.NetworkResourceType documentation for available resource types.
 //   - rod.Hijack documentation for available methods on the Hijack object.
-func (b *Bot) Hijack(patterns []string, networkResourceType proto.NetworkResourceType, handler HijackHandler, continueRequest bool) {
+func (b *Bot) Hijack(
+	patterns []string, networkResourceType proto.NetworkResourceType, handler HijackHandler, continueRequest bool,
+) *HijackRuleHandle {
 	if len(patterns) == 0 {
-		return
+		return nil
 	}
 
-	router := b.browser.HijackRequests()
-
+	rules := make([]HijackRule, 0, len(patterns))
 	for _, pattern := range patterns {
-		router.MustAdd(pattern, func(ctx *rod.Hijack) {
-			if ctx.Request.Type() == networkResourceType {
-				handler(ctx)
-
-				if !continueRequest {
-					return
-				}
-			}
-
-			ctx.ContinueRequest(&proto.FetchContinueRequest{})
+		rules = append(rules, HijackRule{
+			Pattern:      pattern,
+			ResourceType: networkResourceType,
+			Handler:      continueAfter(handler, continueRequest),
 		})
 	}
 
-	go router.Run()
+	handle, err := b.addHijackRules(rules...)
+	b.pie(err)
+
+	return handle
+}
+
+// continueAfter continues the request after handler if continueRequest is true.
+func continueAfter(handler HijackHandler, continueRequest bool) HijackHandler {
+	return func(ctx *rod.Hijack) {
+		handler(ctx)
+
+		if continueRequest {
+			ctx.ContinueRequest(continuePayload(ctx))
+		}
+	}
 }
 
 // DisableImages blocks all image requests for the bot's browser session.
@@ -239,9 +248,10 @@ func (b *Bot) Hijack(patterns []string, networkResourceType proto.NetworkResourc
 //
 // Note:
 //   - This method is useful for reducing bandwidth usage and speeding up page loads.
-//   - It affects all subsequent page loads until the bot is reset or the browser is closed.
-func (b *Bot) DisableImages() {
-	b.Hijack([]string{"*"},
+//   - It affects all subsequent page loads until the returned handle is removed, or StopHijack is called.
+//   - To block fonts, media, trackers etc. and count blocked requests, see BlockResources.
+func (b *Bot) DisableImages() *HijackRuleHandle {
+	return b.Hijack([]string{"*"},
 		proto.NetworkResourceTypeImage,
 		func(h *rod.Hijack) {
 			h.Response.Fail(proto.NetworkErrorReasonBlockedByClient)
@@ -279,8 +289,8 @@ func (b *Bot) DisableImages() {
 //   - Overly broad patterns may intercept more requests than intended.
 //   - Intercepting many XHRs may impact performance. Use specific patterns when possible.
 //   - Modifying XHR responses can affect the functionality of the web application being automated.
-func (b *Bot) DumpXHR(ptn []string, handler func(h *rod.Hijack)) {
-	b.Hijack(ptn,
+func (b *Bot) DumpXHR(ptn []string, handler func(h *rod.Hijack)) *HijackRuleHandle {
+	return b.Hijack(ptn,
 		proto.NetworkResourceTypeXHR,
 		func(h *rod.Hijack) {
 			// h.MustLoadResponse()
@@ -0,0 +1,330 @@
+package wee
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"sort"
+	"sync"
+	"unsafe"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+var (
+	ErrNilHijackHandler = errors.New("hijack rule without handler")
+	ErrBrowserHijacked  = errors.New("browser is intercepted by another bot")
+)
+
+// _hijackedBrowsers are browsers with a running router, by browser.
+// Fetch is enabled and disabled for the whole browser, so only one bot of a browser can run a router.
+var _hijackedBrowsers sync.Map
+
+// HijackRule is one interception rule of the bot's router, see AddHijackRule.
+type HijackRule struct {
+	// Pattern is the same as proto.FetchRequestPattern.URLPattern, "" matches all.
+	Pattern string
+	// ResourceType limits the rule to one resource type, "" matches all.
+	ResourceType proto.NetworkResourceType
+	// Match is an optional check after Pattern and ResourceType.
+	Match func(*rod.Hijack) bool
+	// Priority, rules with higher priority run first, rules with same priority run in the order added.
+	Priority int
+
+	Handler HijackHandler
+}
+
+// HijackRuleHandle removes the rules it was returned with.
+type HijackRuleHandle struct {
+	router *hijackRouter
+	ids    []uint64
+}
+
+// Remove removes the rules from the bot's router, it's safe to call more than once or on a nil handle.
+// The router is stopped when no rule is left.
+func (h *HijackRuleHandle) Remove() error {
+	if h == nil || h.router == nil {
+		return nil
+	}
+
+	return h.router.remove(h.ids...)
+}
+
+// hijackRouter is the single browser level router owned by a bot.
+// The rod router only has one catch-all handler, rules are dispatched by dispatch,
+// so they can be added and removed while the router is running.
+type hijackRouter struct {
+	mu      sync.Mutex
+	router  *rod.HijackRouter
+	browser *rod.Browser
+	rules   []*hijackRule
+	seq     uint64
+	// auth answers proxy auth challenges, see WithProxyAuth.
+	auth *proxyAuth
+	// stopAuth stops the listener of auth challenges.
+	stopAuth func()
+}
+
+type hijackRule struct {
+	HijackRule
+	id  uint64
+	reg *regexp.Regexp
+	// rewrite rules run before other rules, and never resolve the request, see AddRewriteRule.
+	rewrite bool
+}
+
+// AddHijackRule adds rule to the bot's router, the router is started on the first rule.
+//
+// For each paused request, matched rules run in order of priority:
+//   - if the handler sets `ctx.Skip = true`, the request is passed to next matched rule.
+//   - otherwise the request is resolved as rod does: continued if `ctx.ContinueRequest` is called,
+//     failed if `ctx.Response.Fail` is called, else fulfilled with `ctx.Response`.
+//
+// A request matched by no rule, or skipped by all of them, is continued with the changes of rewrite rules,
+// see AddRewriteRule, a handler calling `ctx.ContinueRequest` itself continues with its own payload.
+//
+// The router intercepts requests of the whole browser, so only one bot of a browser can add rules,
+// the others get ErrBrowserHijacked until it's stopped.
+//
+// Usage:
+//
+//	h, err := bot.AddHijackRule(wee.HijackRule{
+//	    Pattern:  "*api/data*",
+//	    Priority: 10,
+//	    Handler: func(ctx *rod.Hijack) {
+//	        ctx.Response.SetBody(`{"mocked":true}`)
+//	    },
+//	})
+//	defer h.Remove()
+func (b *Bot) AddHijackRule(rule HijackRule) (*HijackRuleHandle, error) {
+	id, err := b.hijacker.add(b.browser, rule, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HijackRuleHandle{router: b.hijacker, ids: []uint64{id}}, nil
+}
+
+func (b *Bot) MustAddHijackRule(rule HijackRule) *HijackRuleHandle {
+	h, err := b.AddHijackRule(rule)
+	b.pie(err)
+
+	return h
+}
+
+// addHijackRules adds all rules and returns one handle to remove them together.
+func (b *Bot) addHijackRules(rules ...HijackRule) (*HijackRuleHandle, error) {
+	handle := &HijackRuleHandle{router: b.hijacker}
+
+	for _, rule := range rules {
+		id, err := b.hijacker.add(b.browser, rule, false)
+		if err != nil {
+			_ = handle.Remove()
+			return nil, err
+		}
+
+		handle.ids = append(handle.ids, id)
+	}
+
+	return handle, nil
+}
+
+// StopHijack removes all rules and stops the bot's router,
+// the router is kept running without rules when it answers proxy auth challenges, see WithProxyAuth.
+func (b *Bot) StopHijack() error {
+	return b.hijacker.stop()
+}
+
+func newHijackRouter() *hijackRouter {
+	return &hijackRouter{}
+}
+
+func (r *hijackRouter) add(browser *rod.Browser, rule HijackRule, rewrite bool) (uint64, error) {
+	if rule.Handler == nil {
+		return 0, ErrNilHijackHandler
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.start(browser); err != nil {
+		return 0, err
+	}
+
+	r.seq++
+	r.rules = append(r.rules, &hijackRule{
+		HijackRule: rule,
+		id:         r.seq,
+		reg:        regexp.MustCompile(proto.PatternToReg(StrAorB(rule.Pattern, "*"))),
+		rewrite:    rewrite,
+	})
+
+	sort.SliceStable(r.rules, func(i, j int) bool {
+		return r.rules[i].Priority > r.rules[j].Priority
+	})
+
+	return r.seq, nil
+}
+
+// start starts the rod router if it's not running, r.mu must be held.
+func (r *hijackRouter) start(browser *rod.Browser) error {
+	if r.router != nil {
+		return nil
+	}
+
+	if owner, loaded := _hijackedBrowsers.LoadOrStore(browser, r); loaded && owner != r {
+		return ErrBrowserHijacked
+	}
+
+	// not bound to the context of a WithContext copy, the router lives until StopHijack.
+	router := browser.Context(context.Background()).HijackRequests()
+	if err := router.Add("*", "", r.dispatch); err != nil {
+		_ = router.Stop()
+		_hijackedBrowsers.Delete(browser)
+
+		return err
+	}
+
+	if r.auth != nil {
+		if err := enableFetchAuth(browser, true); err != nil {
+			_ = router.Stop()
+			_hijackedBrowsers.Delete(browser)
+
+			return err
+		}
+	}
+
+	go router.Run()
+
+	r.router, r.browser = router, browser
+
+	return nil
+}
+
+func (r *hijackRouter) remove(ids ...uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.rules[:0]
+
+	for _, rule := range r.rules {
+		removed := false
+
+		for _, id := range ids {
+			if rule.id == id {
+				removed = true
+				break
+			}
+		}
+
+		if !removed {
+			kept = append(kept, rule)
+		}
+	}
+
+	r.rules = kept
+
+	if len(r.rules) != 0 {
+		return nil
+	}
+
+	return r.stopRouter()
+}
+
+func (r *hijackRouter) stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rules = nil
+
+	return r.stopRouter()
+}
+
+// stopRouter stops the rod router, it disables the Fetch domain so page caching is back.
+// The router keeps running without rules while it answers proxy auth challenges.
+func (r *hijackRouter) stopRouter() error {
+	if r.router == nil || r.auth != nil {
+		return nil
+	}
+
+	router := r.router
+	r.router = nil
+
+	_hijackedBrowsers.Delete(r.browser)
+
+	return router.Stop()
+}
+
+// snapshot returns a copy of rules, so rules can be changed while requests are handled.
+func (r *hijackRouter) snapshot() []*hijackRule {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*hijackRule(nil), r.rules...)
+}
+
+func (r *hijackRouter) dispatch(ctx *rod.Hijack) {
+	rules := r.snapshot()
+
+	// rewrites are collected into the payload used when the request is continued.
+	cq := &proto.FetchContinueRequest{}
+	ctx.Request.SetContext(context.WithValue(ctx.Request.Req().Context(), continuePayloadKey{}, cq))
+
+	for _, rule := range rules {
+		if rule.rewrite && rule.matches(ctx) {
+			rule.Handler(ctx)
+		}
+	}
+
+	for _, rule := range rules {
+		if rule.rewrite || !rule.matches(ctx) {
+			continue
+		}
+
+		ctx.Skip = false
+		rule.Handler(ctx)
+
+		if !ctx.Skip {
+			return
+		}
+	}
+
+	ctx.Skip = false
+	ctx.ContinueRequest(continuePayload(ctx))
+}
+
+type continuePayloadKey struct{}
+
+// continuePayload returns the payload to continue the request with, it carries the changes of rewrite rules.
+func continuePayload(ctx *rod.Hijack) *proto.FetchContinueRequest {
+	if cq, ok := ctx.Request.Req().Context().Value(continuePayloadKey{}).(*proto.FetchContinueRequest); ok {
+		return cq
+	}
+
+	return &proto.FetchContinueRequest{}
+}
+
+func (r *hijackRule) matches(ctx *rod.Hijack) bool {
+	if !r.reg.MatchString(ctx.Request.URL().String()) {
+		return false
+	}
+
+	if r.ResourceType != "" && ctx.Request.Type() != r.ResourceType {
+		return false
+	}
+
+	return r.Match == nil || r.Match(ctx)
+}
+
+// pausedEvent returns the Fetch.requestPaused event of ctx, e.g. for its NetworkID or FrameID,
+// rod keeps it unexported in HijackRequest. An empty event is returned if rod changes the field.
+func pausedEvent(ctx *rod.Hijack) *proto.FetchRequestPaused {
+	field := reflect.ValueOf(ctx.Request).Elem().FieldByName("event")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(&proto.FetchRequestPaused{}) || field.IsNil() {
+		return &proto.FetchRequestPaused{}
+	}
+
+	return (*proto.FetchRequestPaused)(unsafe.Pointer(field.Pointer())) //nolint:gosec
+}
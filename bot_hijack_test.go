@@ -3,6 +3,7 @@ package wee
 import (
 	"fmt"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -32,6 +33,10 @@ func (s *BotHijackSuite) TearDownSuite() {
 	s.ts.Close()
 }
 
+func (s *BotHijackSuite) TearDownTest() {
+	s.Require().NoError(s.bot.StopHijack())
+}
+
 func (s *BotHijackSuite) TestHijackAny() {
 	intercepted := false
 	resourceURL := ""
@@ -141,3 +146,85 @@ func (s *BotHijackSuite) TestHijackWithMultiplePatterns() {
 	s.bot.MustOpen(s.ts.URL + "/hijack_test_multiple")
 	s.GreaterOrEqual(interceptCount, 1, "Hijack should have intercepted at least one stylesheet")
 }
+
+func (s *BotHijackSuite) TestRulePriority() {
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+
+	record := func(name string, skip bool) HijackHandler {
+		return func(h *rod.Hijack) {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+
+			h.Skip = skip
+		}
+	}
+
+	const ptn = "*test-script.js?resource=order*"
+
+	s.bot.MustAddHijackRule(HijackRule{Pattern: ptn, Handler: record("low", true)})
+	high := s.bot.MustAddHijackRule(HijackRule{Pattern: ptn, Priority: 10, Handler: record("high", true)})
+
+	s.bot.MustOpen(s.ts.URL + "/hijack_test?resource=order")
+	s.bot.page.MustWaitRequestIdle()
+	s.Equal([]string{"high", "low"}, order, "higher priority runs first, skipped request goes to next rule")
+
+	s.Require().NoError(high.Remove())
+	s.Require().NoError(high.Remove(), "remove twice is a no-op")
+
+	order = nil
+
+	s.bot.MustOpen(s.ts.URL + "/hijack_test?resource=order")
+	s.bot.page.MustWaitRequestIdle()
+	s.Equal([]string{"low"}, order)
+}
+
+func (s *BotHijackSuite) TestOneBotPerBrowser() {
+	rule := HijackRule{Pattern: "*api/data*", Handler: func(h *rod.Hijack) { h.Skip = true }}
+
+	s.bot.MustAddHijackRule(rule)
+
+	other := &Bot{browser: s.bot.browser, hijacker: newHijackRouter()}
+
+	_, err := other.AddHijackRule(rule)
+	s.ErrorIs(err, ErrBrowserHijacked, "the router of the browser is running")
+
+	s.Require().NoError(s.bot.StopHijack())
+
+	_, err = other.AddHijackRule(rule)
+	s.Require().NoError(err, "the browser is free after StopHijack")
+	s.Require().NoError(other.StopHijack())
+}
+
+func (s *BotHijackSuite) TestRuleFulfill() {
+	s.bot.MustAddHijackRule(HijackRule{
+		Pattern: "*api/data*",
+		Handler: func(h *rod.Hijack) {
+			h.Response.SetBody(`{"message": "mocked"}`)
+		},
+	})
+
+	s.bot.MustAddHijackRule(HijackRule{
+		Pattern: "*api/data*",
+		Handler: func(h *rod.Hijack) {
+			s.Fail("request is resolved by previous rule")
+		},
+	})
+
+	s.bot.MustOpen(s.ts.URL + "/xhr_test")
+	got := s.bot.page.MustEval(`() => fetch('/api/data').then(r => r.json()).then(d => d.message)`).Str()
+	s.Equal("mocked", got)
+
+	s.Require().NoError(s.bot.StopHijack())
+
+	got = s.bot.page.MustEval(`() => fetch('/api/data').then(r => r.json()).then(d => d.message)`).Str()
+	s.Equal("XHR response", got, "StopHijack removes all rules")
+}
+
+func (s *BotHijackSuite) TestNilHandler() {
+	_, err := s.bot.AddHijackRule(HijackRule{Pattern: "*"})
+	s.ErrorIs(err, ErrNilHijackHandler)
+}
@@ -3,7 +3,6 @@ package wee
 import (
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/input"
@@ -24,11 +23,11 @@ func (b *Bot) MustInput(sel, text string, opts ...ElemOptionFunc) string {
 
 // Input first clear all content, and then input text content.
 func (b *Bot) Input(sel, text string, opts ...ElemOptionFunc) (string, error) {
-	opt := ElemOptions{submit: false, timeout: PT20Sec, clearBeforeInput: true, endWithEscape: false, humanized: b.humanized}
+	opt := ElemOptions{submit: false, timeout: b.mediumTimeout.Seconds(), clearBeforeInput: true, endWithEscape: false, humanized: b.humanized}
 	bindElemOptions(&opt, opts...)
 
 	if opt.humanized {
-		opts = append(opts, WithTimeout(PT20Sec))
+		opts = append(opts, WithTimeout(b.mediumTimeout.Seconds()))
 	}
 
 	// click the input elem to trigger before input
@@ -76,7 +75,9 @@ func (b *Bot) Input(sel, text string, opts ...ElemOptionFunc) (string, error) {
 
 	if opt.submit {
 		if opt.humanized {
-			RandSleepNap()
+			if err := b.sleepNap(); err != nil {
+				return "", err
+			}
 		}
 
 		action, err := elem.KeyActions()
@@ -111,17 +112,18 @@ func (b *Bot) typeAsHuman(elem *rod.Element, text string, humanized bool) error
 	arr := NewStringSlice(text, length, true)
 
 	for _, str := range arr {
-		if err := elem.Timeout(PT20Sec * time.Second).Input(str); err != nil {
+		if err := elem.Timeout(b.mediumTimeout).Input(str); err != nil {
 			return fmt.Errorf("cannot input by humanized: %w", err)
 		}
 
-		SleepPT100Ms()
+		if err := b.sleepPT100Ms(); err != nil {
+			return err
+		}
 	}
 
 	wait := 0.1
-	RandSleep(wait-0.01, wait+0.01) //nolint:mnd
 
-	return nil
+	return b.randSleep(wait-0.01, wait+0.01) //nolint:mnd
 }
 
 func (b *Bot) TypeCharsOneByOne(elem *rod.Element, value string) {
@@ -50,7 +50,7 @@ func CurlIPFromIfconfigCO(bot *Bot) (*IPInfo, error) {
 		return nil, fmt.Errorf("cannot open url: %s, %w", defaultURL, err)
 	}
 
-	raw, err := bot.ElemAttr(`body>pre`, WithTimeout(MediumToSec))
+	raw, err := bot.ElemAttr(`body>pre`, WithTimeout(bot.mediumTimeout.Seconds()))
 	if err != nil {
 		return nil, fmt.Errorf("cannot get ip info: %w", err)
 	}
@@ -0,0 +1,256 @@
+package wee
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// MockRoutesPriority is the priority of mocked routes, they're served before default rules, cache and replay,
+// but blocked resources are still blocked.
+const MockRoutesPriority = 50
+
+var (
+	ErrMockManifestNotFound = errors.New("mock routes manifest not found")
+	ErrInvalidMockRoute     = errors.New("invalid mock route")
+)
+
+// _mockManifests are looked up in order in the dir of MockRoutes.
+var _mockManifests = []string{"routes.yaml", "routes.yml", "routes.json"}
+
+// MockRoute is one route of the manifest of MockRoutes.
+type MockRoute struct {
+	// Pattern is the same as HijackRule.Pattern, e.g. "*api/search*".
+	Pattern string `json:"pattern" yaml:"pattern"`
+	// Method matches all methods if empty.
+	Method string `json:"method" yaml:"method"`
+	// Status defaults to 200.
+	Status int `json:"status" yaml:"status"`
+	// Headers of the response, Content-Type is guessed from the file extension if not set.
+	Headers map[string]string `json:"headers" yaml:"headers"`
+	// File is the body, relative to the manifest dir.
+	File string `json:"file" yaml:"file"`
+	// Body is the inline body when File is empty.
+	Body string `json:"body" yaml:"body"`
+	// Delay in seconds before the response is sent.
+	Delay float64 `json:"delay" yaml:"delay"`
+
+	body []byte
+}
+
+// RouteMocks is returned by MockRoutes, it records unmatched api calls until removed.
+type RouteMocks struct {
+	handle *HijackRuleHandle
+	logger *zap.Logger
+
+	mu        sync.Mutex
+	unmatched []string
+}
+
+// MockRoutes answers requests of the bot, like XHR and fetch calls, from files in dir, the real front-end is loaded as is.
+//
+// Routes are defined in the manifest "routes.yaml" (or "routes.yml", "routes.json") in dir,
+// they're matched in the order defined, the first matched one is served:
+//
+//   - pattern: "*api/search*"
+//     method: GET
+//     file: search.json
+//     delay: 0.5
+//   - pattern: "*api/login*"
+//     method: POST
+//     status: 401
+//     headers: {Content-Type: application/json}
+//     body: '{"error": "bad password"}'
+//
+// XHR and fetch calls matched by no route go to network, and are logged as warning, see RouteMocks.Unmatched.
+//
+// Usage:
+//
+//	mocks := bot.MustMockRoutes("testdata/mocks")
+//	defer mocks.Remove()
+func (b *Bot) MockRoutes(dir string) (*RouteMocks, error) {
+	routes, err := LoadMockRoutes(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	mocks := &RouteMocks{logger: b.logger}
+
+	rules := make([]HijackRule, 0, len(routes)+2)
+
+	for _, route := range routes {
+		rules = append(rules, HijackRule{
+			Pattern: route.Pattern,
+			// rules of same priority run in order added, so the order of manifest wins.
+			Priority: MockRoutesPriority,
+			Match: func(ctx *rod.Hijack) bool {
+				return route.Method == "" || strings.EqualFold(route.Method, ctx.Request.Method())
+			},
+			Handler: func(ctx *rod.Hijack) {
+				route.serve(b.Context(), ctx)
+			},
+		})
+	}
+
+	for _, typ := range []proto.NetworkResourceType{proto.NetworkResourceTypeXHR, proto.NetworkResourceTypeFetch} {
+		rules = append(rules, HijackRule{
+			ResourceType: typ,
+			Priority:     MockRoutesPriority,
+			Handler:      mocks.onUnmatched,
+		})
+	}
+
+	handle, err := b.addHijackRules(rules...)
+	if err != nil {
+		return nil, err
+	}
+
+	mocks.handle = handle
+
+	return mocks, nil
+}
+
+func (b *Bot) MustMockRoutes(dir string) *RouteMocks {
+	mocks, err := b.MockRoutes(dir)
+	b.pie(err)
+
+	return mocks
+}
+
+// LoadMockRoutes reads the manifest in dir and the body files of routes, see MockRoutes.
+func LoadMockRoutes(dir string) ([]*MockRoute, error) {
+	var (
+		raw      []byte
+		manifest string
+	)
+
+	for _, name := range _mockManifests {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			raw, manifest = content, name
+			break
+		}
+	}
+
+	if manifest == "" {
+		return nil, fmt.Errorf("%w: %s in %s", ErrMockManifestNotFound, strings.Join(_mockManifests, "/"), dir)
+	}
+
+	var routes []*MockRoute
+
+	// json is valid yaml, unknown keys are refused so a typo doesn't silently drop a field.
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(&routes); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidMockRoute, manifest, err)
+	}
+
+	for i, route := range routes {
+		if err := route.load(dir); err != nil {
+			return nil, fmt.Errorf("%w: %s #%d: %w", ErrInvalidMockRoute, manifest, i+1, err)
+		}
+	}
+
+	return routes, nil
+}
+
+func (r *MockRoute) load(dir string) error {
+	if r.Pattern == "" {
+		return errors.New("empty pattern")
+	}
+
+	if r.Status == 0 {
+		r.Status = 200
+	}
+
+	r.body = []byte(r.Body)
+
+	if r.File != "" {
+		content, err := os.ReadFile(filepath.Join(dir, r.File))
+		if err != nil {
+			return err
+		}
+
+		r.body = content
+	}
+
+	if !hasHeader(r.Headers, "Content-Type") {
+		if r.Headers == nil {
+			r.Headers = make(map[string]string)
+		}
+
+		r.Headers["Content-Type"] = StrAorB(mime.TypeByExtension(filepath.Ext(r.File)), "application/json")
+	}
+
+	return nil
+}
+
+// serve fulfills the request after Delay, the request is aborted if the bot's context is done while waiting.
+func (r *MockRoute) serve(botCtx context.Context, ctx *rod.Hijack) {
+	if r.Delay > 0 {
+		if _, err := RandSleepCtx(botCtx, r.Delay, r.Delay); err != nil {
+			ctx.Response.Fail(proto.NetworkErrorReasonAborted)
+			return
+		}
+	}
+
+	ctx.Response.Payload().ResponseCode = r.Status
+
+	for k, v := range r.Headers {
+		ctx.Response.SetHeader(k, v)
+	}
+
+	ctx.Response.SetBody(r.body)
+}
+
+// Remove stops mocking, unmatched calls are kept.
+func (m *RouteMocks) Remove() error {
+	if m == nil {
+		return nil
+	}
+
+	return m.handle.Remove()
+}
+
+// Unmatched returns "METHOD url" of XHR and fetch calls not matched by any route.
+func (m *RouteMocks) Unmatched() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]string(nil), m.unmatched...)
+}
+
+func (m *RouteMocks) onUnmatched(ctx *rod.Hijack) {
+	call := ctx.Request.Method() + " " + ctx.Request.URL().String()
+
+	m.mu.Lock()
+	m.unmatched = append(m.unmatched, call)
+	m.mu.Unlock()
+
+	m.logger.Warn("unmatched api call", zap.String("call", call))
+
+	ctx.Skip = true
+}
+
+func hasHeader(headers map[string]string, name string) bool {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+
+	return false
+}
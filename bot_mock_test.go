@@ -0,0 +1,107 @@
+package wee
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coghost/wee/fixtures"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockSuite struct {
+	suite.Suite
+}
+
+func TestMock(t *testing.T) {
+	suite.Run(t, new(MockSuite))
+}
+
+func (s *MockSuite) writeFiles(files map[string]string) string {
+	dir := s.T().TempDir()
+
+	for name, content := range files {
+		s.Require().NoError(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	return dir
+}
+
+func (s *MockSuite) TestLoad() {
+	dir := s.writeFiles(map[string]string{
+		"routes.yaml": `
+- pattern: "*api/data*"
+  file: data.json
+  delay: 0.1
+- pattern: "*api/login*"
+  method: POST
+  status: 401
+  headers: {content-type: text/plain}
+  body: denied
+- pattern: "*page*"
+  file: page.html
+`,
+		"data.json": `{"message": "mocked"}`,
+		"page.html": `<p>hi</p>`,
+	})
+
+	routes, err := LoadMockRoutes(dir)
+	s.Require().NoError(err)
+	s.Require().Len(routes, 3)
+
+	s.Equal(200, routes[0].Status)
+	s.Equal(`{"message": "mocked"}`, string(routes[0].body))
+	s.Equal("application/json", routes[0].Headers["Content-Type"])
+
+	s.Equal(401, routes[1].Status)
+	s.Equal("denied", string(routes[1].body))
+	s.Equal(map[string]string{"content-type": "text/plain"}, routes[1].Headers)
+
+	s.True(strings.HasPrefix(routes[2].Headers["Content-Type"], "text/html"))
+}
+
+func (s *MockSuite) TestLoadErrors() {
+	_, err := LoadMockRoutes(s.T().TempDir())
+	s.ErrorIs(err, ErrMockManifestNotFound)
+
+	_, err = LoadMockRoutes(s.writeFiles(map[string]string{"routes.json": `[{"pattern": "*", "file": "missing.json"}]`}))
+	s.ErrorIs(err, ErrInvalidMockRoute)
+
+	_, err = LoadMockRoutes(s.writeFiles(map[string]string{"routes.json": `[{"file": "x.json"}]`}))
+	s.ErrorIs(err, ErrInvalidMockRoute)
+
+	_, err = LoadMockRoutes(s.writeFiles(map[string]string{"routes.yml": `pattern: [`}))
+	s.ErrorIs(err, ErrInvalidMockRoute)
+
+	_, err = LoadMockRoutes(s.writeFiles(map[string]string{"routes.yaml": "- pattern: '*'\n  stauts: 404\n"}))
+	s.ErrorIs(err, ErrInvalidMockRoute, "unknown key")
+}
+
+func (s *MockSuite) TestBot() {
+	ts := fixtures.NewTestServer()
+	defer ts.Close()
+
+	dir := s.writeFiles(map[string]string{
+		"routes.json": `[{"pattern": "*api/data*", "file": "data.json", "headers": {"X-Mocked": "1"}}]`,
+		"data.json":   `{"message": "from file"}`,
+	})
+
+	bot := NewBotHeadless()
+	defer bot.Cleanup()
+
+	mocks := bot.MustMockRoutes(dir)
+	defer mocks.Remove()
+
+	bot.MustOpen(ts.URL + "/xhr_test")
+
+	got := bot.page.MustEval(`() => fetch('/api/data').then(r => r.headers.get('X-Mocked') + ':' + r.status)`).Str()
+	s.True(strings.HasPrefix(got, "1:200"), got)
+
+	msg := bot.page.MustEval(`() => fetch('/api/data').then(r => r.json()).then(d => d.message)`).Str()
+	s.Equal("from file", msg)
+
+	ua := bot.page.MustEval(`() => fetch('/user_agent').then(r => r.text())`).Str()
+	s.NotEmpty(ua, "unmatched call goes to network")
+	s.Contains(mocks.Unmatched(), "GET "+ts.URL+"/user_agent")
+}
@@ -0,0 +1,429 @@
+package wee
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/gookit/goutil/fsutil"
+	"go.uber.org/zap"
+)
+
+var ErrNetworkLogStarted = errors.New("network log already started")
+
+// NetworkLogRecord is one line of the network log, written when a request is finished, failed or redirected.
+type NetworkLogRecord struct {
+	// Time is when the request started.
+	Time         time.Time                 `json:"time"`
+	RequestID    string                    `json:"request_id"`
+	URL          string                    `json:"url"`
+	Method       string                    `json:"method"`
+	ResourceType proto.NetworkResourceType `json:"resource_type"`
+	Status       int                       `json:"status,omitempty"`
+	MIMEType     string                    `json:"mime_type,omitempty"`
+	// Size is the encoded bytes received, including headers.
+	Size int `json:"size"`
+	// Duration is from request start to finish or failure.
+	Duration float64 `json:"duration_ms"`
+	// TTFB is from request sent to response headers received, 0 if unknown.
+	TTFB float64 `json:"ttfb_ms,omitempty"`
+	// Initiator is the devtools initiator type, like "parser", "script" or "preflight".
+	Initiator    proto.NetworkInitiatorType `json:"initiator,omitempty"`
+	InitiatorURL string                     `json:"initiator_url,omitempty"`
+	RedirectURL  string                     `json:"redirect_url,omitempty"`
+	FromCache    bool                       `json:"from_cache,omitempty"`
+	// Error is the failure reason, e.g. "net::ERR_BLOCKED_BY_CLIENT".
+	Error    string `json:"error,omitempty"`
+	Canceled bool   `json:"canceled,omitempty"`
+	// Pending is set for requests still in flight when the log is stopped.
+	Pending bool `json:"pending,omitempty"`
+}
+
+type NetworkLogOptions struct {
+	resourceTypes []proto.NetworkResourceType
+	patterns      []string
+}
+
+type NetworkLogOptionFunc func(o *NetworkLogOptions)
+
+func bindNetworkLogOptions(opt *NetworkLogOptions, opts ...NetworkLogOptionFunc) {
+	for _, f := range opts {
+		f(opt)
+	}
+}
+
+// WithNetworkLogTypes only logs requests of types, default is all.
+func WithNetworkLogTypes(types ...proto.NetworkResourceType) NetworkLogOptionFunc {
+	return func(o *NetworkLogOptions) {
+		o.resourceTypes = types
+	}
+}
+
+// WithNetworkLogPatterns only logs requests matching any of url patterns, default is all.
+func WithNetworkLogPatterns(patterns ...string) NetworkLogOptionFunc {
+	return func(o *NetworkLogOptions) {
+		o.patterns = patterns
+	}
+}
+
+// WithNetworkLog starts the network log to w when the page is created, see StartNetworkLog.
+func WithNetworkLog(w io.Writer, opts ...NetworkLogOptionFunc) BotOption {
+	return func(o *Bot) {
+		o.networkLogWriter = w
+		o.networkLogOptions = opts
+	}
+}
+
+// WithNetworkLogFile starts the network log to file path when the page is created, the file is appended.
+func WithNetworkLogFile(path string, opts ...NetworkLogOptionFunc) BotOption {
+	return func(o *Bot) {
+		o.networkLogFile = path
+		o.networkLogOptions = opts
+	}
+}
+
+// NetworkLogger writes the traffic of the bot's page as JSON lines, see StartNetworkLog.
+type NetworkLogger struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	closer io.Closer
+	logger *zap.Logger
+	state  *networkLogState
+
+	types    map[proto.NetworkResourceType]bool
+	patterns []*regexp.Regexp
+
+	mu      sync.Mutex
+	enc     *json.Encoder
+	pending map[proto.NetworkRequestID]*networkLogPending
+	err     error
+}
+
+type networkLogPending struct {
+	record  *NetworkLogRecord
+	started proto.MonotonicTime
+}
+
+// StartNetworkLog writes one NetworkLogRecord per request of the bot's page to w as a JSON line,
+// so crawl traffic can be processed with tools like jq.
+//
+// It listens to the devtools network events, nothing is intercepted.
+// A redirect is logged as one line with redirect_url set, then the next hop is logged as a new line.
+//
+// Usage:
+//
+//	f, _ := os.Create("traffic.jsonl")
+//	nl, err := bot.StartNetworkLog(f, wee.WithNetworkLogTypes(proto.NetworkResourceTypeXHR, proto.NetworkResourceTypeFetch))
+//	defer nl.Stop()
+//
+//	// then: jq -r 'select(.status >= 400) | .url' traffic.jsonl
+func (b *Bot) StartNetworkLog(w io.Writer, opts ...NetworkLogOptionFunc) (*NetworkLogger, error) {
+	if b.netLog.get() != nil {
+		return nil, ErrNetworkLogStarted
+	}
+
+	nl, err := newNetworkLogger(w, b.logger, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	nl.cancel = cancel
+	nl.done = make(chan struct{})
+	nl.state = b.netLog
+
+	wait := b.page.Context(ctx).EachEvent(
+		nl.onRequest,
+		nl.onResponse,
+		nl.onFinished,
+		nl.onFailed,
+	)
+
+	go func() {
+		defer close(nl.done)
+		wait()
+	}()
+
+	b.netLog.set(nl)
+
+	return nl, nil
+}
+
+func (b *Bot) MustStartNetworkLog(w io.Writer, opts ...NetworkLogOptionFunc) *NetworkLogger {
+	nl, err := b.StartNetworkLog(w, opts...)
+	b.pie(err)
+
+	return nl
+}
+
+// StopNetworkLog stops the log started by StartNetworkLog or the WithNetworkLog options.
+func (b *Bot) StopNetworkLog() error {
+	return b.netLog.get().Stop()
+}
+
+// startNetworkLogByOptions starts the log set by WithNetworkLog or WithNetworkLogFile.
+func (b *Bot) startNetworkLogByOptions() error {
+	if b.netLog.get() != nil {
+		return nil
+	}
+
+	w := b.networkLogWriter
+
+	var closer io.Closer
+
+	if b.networkLogFile != "" {
+		if err := fsutil.MkParentDir(b.networkLogFile); err != nil {
+			return err
+		}
+
+		f, err := os.OpenFile(b.networkLogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, _cookieFilePermissions)
+		if err != nil {
+			return fmt.Errorf("cannot open network log: %w", err)
+		}
+
+		w, closer = f, f
+	}
+
+	if w == nil {
+		return nil
+	}
+
+	nl, err := b.StartNetworkLog(w, b.networkLogOptions...)
+	if err != nil {
+		if closer != nil {
+			_ = closer.Close()
+		}
+
+		return err
+	}
+
+	nl.closer = closer
+
+	return nil
+}
+
+// networkLogState holds the running logger, shared by WithContext copies of the bot.
+type networkLogState struct {
+	mu sync.Mutex
+	nl *NetworkLogger
+}
+
+func (s *networkLogState) get() *NetworkLogger {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.nl
+}
+
+func (s *networkLogState) set(nl *NetworkLogger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nl = nl
+}
+
+// release clears nl if it's still the running one.
+func (s *networkLogState) release(nl *NetworkLogger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.nl == nl {
+		s.nl = nil
+	}
+}
+
+func newNetworkLogger(w io.Writer, logger *zap.Logger, opts ...NetworkLogOptionFunc) (*NetworkLogger, error) {
+	opt := &NetworkLogOptions{}
+	bindNetworkLogOptions(opt, opts...)
+
+	nl := &NetworkLogger{
+		logger:  logger,
+		enc:     json.NewEncoder(w),
+		pending: make(map[proto.NetworkRequestID]*networkLogPending),
+	}
+
+	if len(opt.resourceTypes) != 0 {
+		nl.types = make(map[proto.NetworkResourceType]bool)
+		for _, t := range opt.resourceTypes {
+			nl.types[t] = true
+		}
+	}
+
+	for _, ptn := range opt.patterns {
+		reg, err := regexp.Compile(proto.PatternToReg(ptn))
+		if err != nil {
+			return nil, fmt.Errorf("invalid network log pattern %q: %w", ptn, err)
+		}
+
+		nl.patterns = append(nl.patterns, reg)
+	}
+
+	return nl, nil
+}
+
+// Stop stops logging, requests in flight are written with pending set,
+// the file opened by WithNetworkLogFile is closed. It returns the first write error if any.
+func (nl *NetworkLogger) Stop() error {
+	if nl == nil {
+		return nil
+	}
+
+	if nl.cancel != nil {
+		nl.cancel()
+		<-nl.done
+	}
+
+	if nl.state != nil {
+		nl.state.release(nl)
+	}
+
+	nl.mu.Lock()
+	defer nl.mu.Unlock()
+
+	for id, p := range nl.pending {
+		p.record.Pending = true
+		nl.write(p.record)
+		delete(nl.pending, id)
+	}
+
+	if nl.closer != nil {
+		if err := nl.closer.Close(); err != nil && nl.err == nil {
+			nl.err = err
+		}
+
+		nl.closer = nil
+	}
+
+	return nl.err
+}
+
+func (nl *NetworkLogger) matches(uri string, typ proto.NetworkResourceType) bool {
+	if nl.types != nil && !nl.types[typ] {
+		return false
+	}
+
+	if len(nl.patterns) == 0 {
+		return true
+	}
+
+	for _, reg := range nl.patterns {
+		if reg.MatchString(uri) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (nl *NetworkLogger) onRequest(e *proto.NetworkRequestWillBeSent) {
+	nl.mu.Lock()
+	defer nl.mu.Unlock()
+
+	// a redirect reuses the request id, log the previous hop.
+	if prev, ok := nl.pending[e.RequestID]; ok && e.RedirectResponse != nil {
+		prev.record.RedirectURL = e.Request.URL
+		nl.setResponse(prev.record, e.RedirectResponse)
+		nl.finish(e.RequestID, prev, e.Timestamp)
+	}
+
+	uri := e.Request.URL + e.Request.URLFragment
+	if !nl.matches(uri, e.Type) {
+		return
+	}
+
+	record := &NetworkLogRecord{
+		Time:         e.WallTime.Time(),
+		RequestID:    string(e.RequestID),
+		URL:          uri,
+		Method:       e.Request.Method,
+		ResourceType: e.Type,
+	}
+
+	if e.Initiator != nil {
+		record.Initiator = e.Initiator.Type
+		record.InitiatorURL = initiatorURL(e.Initiator)
+	}
+
+	nl.pending[e.RequestID] = &networkLogPending{record: record, started: e.Timestamp}
+}
+
+func (nl *NetworkLogger) onResponse(e *proto.NetworkResponseReceived) {
+	nl.mu.Lock()
+	defer nl.mu.Unlock()
+
+	if p, ok := nl.pending[e.RequestID]; ok {
+		nl.setResponse(p.record, e.Response)
+	}
+}
+
+func (nl *NetworkLogger) onFinished(e *proto.NetworkLoadingFinished) {
+	nl.mu.Lock()
+	defer nl.mu.Unlock()
+
+	if p, ok := nl.pending[e.RequestID]; ok {
+		p.record.Size = int(e.EncodedDataLength)
+		nl.finish(e.RequestID, p, e.Timestamp)
+	}
+}
+
+func (nl *NetworkLogger) onFailed(e *proto.NetworkLoadingFailed) {
+	nl.mu.Lock()
+	defer nl.mu.Unlock()
+
+	if p, ok := nl.pending[e.RequestID]; ok {
+		p.record.Error = StrAorB(e.ErrorText, string(e.BlockedReason))
+		p.record.Canceled = e.Canceled
+		nl.finish(e.RequestID, p, e.Timestamp)
+	}
+}
+
+func (nl *NetworkLogger) setResponse(record *NetworkLogRecord, resp *proto.NetworkResponse) {
+	record.Status = resp.Status
+	record.MIMEType = resp.MIMEType
+	record.FromCache = resp.FromDiskCache || resp.FromPrefetchCache || resp.FromServiceWorker
+	record.Size = int(resp.EncodedDataLength)
+
+	if tm := resp.Timing; tm != nil && tm.SendEnd >= 0 && tm.ReceiveHeadersEnd >= tm.SendEnd {
+		record.TTFB = tm.ReceiveHeadersEnd - tm.SendEnd
+	}
+}
+
+func (nl *NetworkLogger) finish(id proto.NetworkRequestID, p *networkLogPending, ts proto.MonotonicTime) {
+	p.record.Duration = max(0, float64(ts-p.started)*float64(time.Second/time.Millisecond))
+	nl.write(p.record)
+	delete(nl.pending, id)
+}
+
+// write encodes record as one line, the first error is kept and returned by Stop.
+func (nl *NetworkLogger) write(record *NetworkLogRecord) {
+	if err := nl.enc.Encode(record); err != nil {
+		if nl.err == nil {
+			nl.err = err
+			nl.logger.Error("cannot write network log", zap.Error(err))
+		}
+	}
+}
+
+// initiatorURL returns the url of initiator, or the top frame of its stack for scripts.
+func initiatorURL(initiator *proto.NetworkInitiator) string {
+	if initiator.URL != "" {
+		return initiator.URL
+	}
+
+	for stack := initiator.Stack; stack != nil; stack = stack.Parent {
+		for _, frame := range stack.CallFrames {
+			if frame.URL != "" {
+				return frame.URL
+			}
+		}
+	}
+
+	return ""
+}
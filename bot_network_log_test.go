@@ -0,0 +1,158 @@
+package wee
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coghost/wee/fixtures"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type NetworkLogSuite struct {
+	suite.Suite
+}
+
+func TestNetworkLog(t *testing.T) {
+	suite.Run(t, new(NetworkLogSuite))
+}
+
+func (s *NetworkLogSuite) readRecords(raw []byte) []*NetworkLogRecord {
+	var records []*NetworkLogRecord
+
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		var r NetworkLogRecord
+		s.Require().NoError(json.Unmarshal(scanner.Bytes(), &r), scanner.Text())
+		records = append(records, &r)
+	}
+
+	return records
+}
+
+func (s *NetworkLogSuite) TestRecordEvents() {
+	var buf bytes.Buffer
+
+	nl, err := newNetworkLogger(&buf, zap.NewNop(), WithNetworkLogPatterns("*example.com*"))
+	s.Require().NoError(err)
+
+	nl.onRequest(&proto.NetworkRequestWillBeSent{
+		RequestID: "1",
+		Request:   &proto.NetworkRequest{URL: "http://example.com/old", Method: "GET"},
+		Timestamp: 10,
+		Type:      proto.NetworkResourceTypeDocument,
+		Initiator: &proto.NetworkInitiator{Type: proto.NetworkInitiatorTypeOther},
+	})
+	nl.onRequest(&proto.NetworkRequestWillBeSent{
+		RequestID:        "1",
+		Request:          &proto.NetworkRequest{URL: "http://example.com/new", Method: "GET"},
+		Timestamp:        10.1,
+		Type:             proto.NetworkResourceTypeDocument,
+		RedirectResponse: &proto.NetworkResponse{Status: 301, EncodedDataLength: 120},
+	})
+	nl.onResponse(&proto.NetworkResponseReceived{
+		RequestID: "1",
+		Response: &proto.NetworkResponse{
+			Status: 200, MIMEType: "text/html",
+			Timing: &proto.NetworkResourceTiming{SendEnd: 2, ReceiveHeadersEnd: 30},
+		},
+	})
+	nl.onFinished(&proto.NetworkLoadingFinished{RequestID: "1", Timestamp: 10.3, EncodedDataLength: 2048})
+
+	nl.onRequest(&proto.NetworkRequestWillBeSent{
+		RequestID: "2",
+		Request:   &proto.NetworkRequest{URL: "http://example.com/app.js", Method: "GET"},
+		Timestamp: 11,
+		Type:      proto.NetworkResourceTypeScript,
+		Initiator: &proto.NetworkInitiator{
+			Type:  proto.NetworkInitiatorTypeScript,
+			Stack: &proto.RuntimeStackTrace{CallFrames: []*proto.RuntimeCallFrame{{URL: "http://example.com/loader.js"}}},
+		},
+	})
+	nl.onFailed(&proto.NetworkLoadingFailed{RequestID: "2", Timestamp: 11.05, ErrorText: "net::ERR_BLOCKED_BY_CLIENT"})
+
+	// filtered out by pattern
+	nl.onRequest(&proto.NetworkRequestWillBeSent{
+		RequestID: "3", Request: &proto.NetworkRequest{URL: "http://other.com/", Method: "GET"}, Timestamp: 12,
+	})
+
+	nl.onRequest(&proto.NetworkRequestWillBeSent{
+		RequestID: "4", Request: &proto.NetworkRequest{URL: "http://example.com/slow", Method: "GET"}, Timestamp: 13,
+	})
+
+	s.Require().NoError(nl.Stop())
+
+	records := s.readRecords(buf.Bytes())
+	s.Require().Len(records, 4)
+
+	s.Equal("http://example.com/old", records[0].URL)
+	s.Equal("http://example.com/new", records[0].RedirectURL)
+	s.Equal(301, records[0].Status)
+	s.Equal(120, records[0].Size)
+	s.InDelta(100, records[0].Duration, 0.01)
+
+	s.Equal(200, records[1].Status)
+	s.Equal(2048, records[1].Size)
+	s.InDelta(200, records[1].Duration, 0.01)
+	s.InDelta(28, records[1].TTFB, 0.01)
+
+	s.Equal("net::ERR_BLOCKED_BY_CLIENT", records[2].Error)
+	s.Equal(proto.NetworkInitiatorTypeScript, records[2].Initiator)
+	s.Equal("http://example.com/loader.js", records[2].InitiatorURL)
+
+	s.Equal("http://example.com/slow", records[3].URL)
+	s.True(records[3].Pending)
+}
+
+func (s *NetworkLogSuite) TestBot() {
+	ts := fixtures.NewTestServer()
+	defer ts.Close()
+
+	file := filepath.Join(s.T().TempDir(), "traffic.jsonl")
+
+	bot := NewBotHeadless(WithNetworkLogFile(file, WithNetworkLogTypes(proto.NetworkResourceTypeFetch)))
+
+	_, err := bot.StartNetworkLog(&bytes.Buffer{})
+	s.ErrorIs(err, ErrNetworkLogStarted)
+
+	bot.MustOpen(ts.URL + "/xhr_test")
+	bot.page.MustWaitRequestIdle()
+	bot.Cleanup()
+
+	raw, err := os.ReadFile(file)
+	s.Require().NoError(err)
+
+	records := s.readRecords(raw)
+	s.Require().Len(records, 1)
+	s.True(strings.HasSuffix(records[0].URL, "/api/data"))
+	s.Equal(200, records[0].Status)
+	s.Equal(proto.NetworkInitiatorTypeScript, records[0].Initiator)
+}
+
+func (s *NetworkLogSuite) TestKeptAfterHARStops() {
+	ts := fixtures.NewTestServer()
+	defer ts.Close()
+
+	bot := NewBotHeadless()
+	defer bot.Cleanup()
+
+	buf := &bytes.Buffer{}
+
+	bot.MustStartHAR()
+	bot.MustStartNetworkLog(buf)
+
+	// the har started first, stopping it must not disable the Network domain of the log.
+	_, err := bot.StopHAR(filepath.Join(s.T().TempDir(), "session.har"))
+	s.Require().NoError(err)
+
+	bot.MustOpen(ts.URL + "/hellowee")
+	s.Require().NoError(bot.StopNetworkLog())
+
+	s.NotEmpty(s.readRecords(buf.Bytes()))
+}
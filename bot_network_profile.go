@@ -0,0 +1,142 @@
+package wee
+
+import (
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// _noThrottle disables download or upload throttling of Network.emulateNetworkConditions.
+const _noThrottle = -1
+
+// NetworkProfile is the network conditions emulated by EmulateNetwork.
+type NetworkProfile struct {
+	// Offline emulates internet disconnection, requests fail with net::ERR_INTERNET_DISCONNECTED.
+	Offline bool
+	// Latency is the minimum time from request sent to response headers received.
+	Latency time.Duration
+	// DownloadThroughput and UploadThroughput in bytes per second, 0 is no limit.
+	DownloadThroughput float64
+	UploadThroughput   float64
+	// ConnectionType is reported to the page by navigator.connection, optional.
+	ConnectionType proto.NetworkConnectionType
+
+	// DisableCache disables the browser cache, so every request goes to network.
+	DisableCache bool
+	// BypassServiceWorker loads requests from network instead of service workers.
+	BypassServiceWorker bool
+}
+
+// NewNetworkProfile creates a custom profile, throughputs are in kbit/s like custom profiles of devtools, 0 is no limit.
+func NewNetworkProfile(latency time.Duration, downloadKbps, uploadKbps float64) *NetworkProfile {
+	return &NetworkProfile{
+		Latency:            latency,
+		DownloadThroughput: kbpsToBytes(downloadKbps),
+		UploadThroughput:   kbpsToBytes(uploadKbps),
+	}
+}
+
+// NewNetworkProfileOffline
+//
+//	@return *NetworkProfile all requests fail
+func NewNetworkProfileOffline() *NetworkProfile {
+	return &NetworkProfile{Offline: true, ConnectionType: proto.NetworkConnectionTypeNone}
+}
+
+// NewNetworkProfileSlow3G is the "Slow 3G" preset of devtools.
+//
+//	@return *NetworkProfile {2s,400kbit/s,400kbit/s}
+func NewNetworkProfileSlow3G() *NetworkProfile {
+	p := NewNetworkProfile(2*time.Second, 400, 400) //nolint:mnd
+	p.ConnectionType = proto.NetworkConnectionTypeCellular3g
+
+	return p
+}
+
+// NewNetworkProfileFast3G is the "Fast 3G" preset of devtools.
+//
+//	@return *NetworkProfile {562.5ms,1440kbit/s,675kbit/s}
+func NewNetworkProfileFast3G() *NetworkProfile {
+	p := NewNetworkProfile(562500*time.Microsecond, 1440, 675) //nolint:mnd
+	p.ConnectionType = proto.NetworkConnectionTypeCellular3g
+
+	return p
+}
+
+// NewNetworkProfile4G is a typical 4G connection.
+//
+//	@return *NetworkProfile {170ms,9000kbit/s,9000kbit/s}
+func NewNetworkProfile4G() *NetworkProfile {
+	p := NewNetworkProfile(170*time.Millisecond, 9000, 9000) //nolint:mnd
+	p.ConnectionType = proto.NetworkConnectionTypeCellular4g
+
+	return p
+}
+
+// WithNetworkProfile emulates the network conditions when the page is created, see EmulateNetwork.
+func WithNetworkProfile(profile *NetworkProfile) BotOption {
+	return func(o *Bot) {
+		o.networkProfile = profile
+	}
+}
+
+// EmulateNetwork throttles or disconnects the network of the bot's page, nil restores the real network.
+//
+// It applies to requests sent after the call, including the ones of the next navigation.
+//
+// Usage:
+//
+//	bot.MustEmulateNetwork(wee.NewNetworkProfileSlow3G())
+//	bot.MustOpen(uri)
+//
+//	// reproduce a flaky connection in the middle of a flow
+//	bot.MustEmulateNetwork(wee.NewNetworkProfileOffline())
+//	bot.MustClick(submit)
+//	bot.MustEmulateNetwork(nil)
+func (b *Bot) EmulateNetwork(profile *NetworkProfile) error {
+	if profile == nil {
+		profile = &NetworkProfile{}
+	}
+
+	return profile.apply(b.page)
+}
+
+func (b *Bot) MustEmulateNetwork(profile *NetworkProfile) {
+	b.pie(b.EmulateNetwork(profile))
+}
+
+// apply emulates the profile, disabling the Network domain drops it, see keepNetworkEnabled.
+func (p *NetworkProfile) apply(page *rod.Page) error {
+	if err := p.conditions().Call(page); err != nil {
+		return err
+	}
+
+	if err := (proto.NetworkSetCacheDisabled{CacheDisabled: p.DisableCache}).Call(page); err != nil {
+		return err
+	}
+
+	return proto.NetworkSetBypassServiceWorker{Bypass: p.BypassServiceWorker}.Call(page)
+}
+
+func (p *NetworkProfile) conditions() proto.NetworkEmulateNetworkConditions {
+	throughput := func(v float64) float64 {
+		if v <= 0 {
+			return _noThrottle
+		}
+
+		return v
+	}
+
+	return proto.NetworkEmulateNetworkConditions{
+		Offline:            p.Offline,
+		Latency:            float64(p.Latency) / float64(time.Millisecond),
+		DownloadThroughput: throughput(p.DownloadThroughput),
+		UploadThroughput:   throughput(p.UploadThroughput),
+		ConnectionType:     p.ConnectionType,
+	}
+}
+
+func kbpsToBytes(kbps float64) float64 {
+	return kbps * 1000 / 8 //nolint:mnd
+}
@@ -0,0 +1,79 @@
+package wee
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/coghost/wee/fixtures"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/suite"
+)
+
+type NetworkProfileSuite struct {
+	suite.Suite
+}
+
+func TestNetworkProfile(t *testing.T) {
+	suite.Run(t, new(NetworkProfileSuite))
+}
+
+func (s *NetworkProfileSuite) TestConditions() {
+	s.Equal(proto.NetworkEmulateNetworkConditions{
+		Latency:            2000,
+		DownloadThroughput: 50000,
+		UploadThroughput:   50000,
+		ConnectionType:     proto.NetworkConnectionTypeCellular3g,
+	}, NewNetworkProfileSlow3G().conditions())
+
+	s.InDelta(562.5, NewNetworkProfileFast3G().conditions().Latency, 0.001)
+
+	s.Equal(proto.NetworkEmulateNetworkConditions{
+		Offline:            true,
+		DownloadThroughput: _noThrottle,
+		UploadThroughput:   _noThrottle,
+		ConnectionType:     proto.NetworkConnectionTypeNone,
+	}, NewNetworkProfileOffline().conditions())
+
+	s.Equal(proto.NetworkEmulateNetworkConditions{
+		Latency:            300,
+		DownloadThroughput: 125000,
+		UploadThroughput:   _noThrottle,
+	}, NewNetworkProfile(300*time.Millisecond, 1000, 0).conditions(), "0 is no limit")
+}
+
+func (s *NetworkProfileSuite) TestBot() {
+	ts := fixtures.NewTestServer()
+	defer ts.Close()
+
+	bot := NewBotHeadless(WithNetworkProfile(NewNetworkProfileOffline()))
+	defer bot.Cleanup()
+
+	s.Error(bot.Open(ts.URL+"/hellowee"), "offline")
+
+	profile := NewNetworkProfile(500*time.Millisecond, 0, 0)
+	profile.DisableCache = true
+	bot.MustEmulateNetwork(profile)
+
+	start := time.Now()
+	s.NoError(bot.Open(ts.URL + "/hellowee"))
+	s.GreaterOrEqual(time.Since(start), 500*time.Millisecond)
+
+	bot.MustEmulateNetwork(nil)
+	s.NoError(bot.Open(ts.URL + "/hellowee"))
+}
+
+func (s *NetworkProfileSuite) TestKeptAfterNetworkLog() {
+	ts := fixtures.NewTestServer()
+	defer ts.Close()
+
+	bot := NewBotHeadless()
+	defer bot.Cleanup()
+
+	// stopping the log must not disable the Network domain, that drops the emulation.
+	bot.MustStartNetworkLog(io.Discard)
+	bot.MustEmulateNetwork(NewNetworkProfileOffline())
+	s.Require().NoError(bot.StopNetworkLog())
+
+	s.Error(bot.Open(ts.URL+"/hellowee"), "still offline")
+}
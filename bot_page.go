@@ -27,31 +27,50 @@ const (
 var (
 	ErrCannotActivateOpenedPage = errors.New("cannot activate latest opened page")
 	ErrMissingCookieFile        = errors.New("missing cookie file")
+	ErrCreatePageFailed         = errors.New("cannot create page")
+	ErrWindowSetupFailed        = errors.New("cannot setup window")
 )
 
 func (b *Bot) CustomizePage() {
+	if err := b.CustomizePageE(); err != nil {
+		panic(err)
+	}
+}
+
+// CustomizePageE is the error-returning version of CustomizePage,
+// it returns ErrCreatePageFailed or ErrWindowSetupFailed instead of panicking.
+func (b *Bot) CustomizePageE() error {
 	if !b.withPageCreation {
-		return
+		return nil
 	}
 
 	if b.launcher == nil {
-		return
+		return nil
+	}
+
+	if err := b.startProxyAuth(); err != nil {
+		return fmt.Errorf("%w: cannot handle proxy auth: %w", ErrCreatePageFailed, err)
 	}
 
 	if b.page == nil {
-		if b.stealthMode && !b.userMode {
-			b.page = stealth.MustPage(b.browser)
-		} else {
-			b.page = b.browser.MustPage()
+		page, err := b.newPage()
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrCreatePageFailed, err)
 		}
+
+		b.page = page
 	}
 
+	b.keepNetworkEnabled()
+
 	ua := b.userAgent
 	lang := b.acceptLanguage
 
 	if ua != "" || lang != "" {
 		ov := overrideUA(ua, lang)
-		b.page = b.page.MustSetUserAgent(ov)
+		if err := b.page.SetUserAgent(ov); err != nil {
+			return fmt.Errorf("%w: cannot set user agent: %w", ErrCreatePageFailed, err)
+		}
 	}
 
 	// display := NewDefaultDisplay()
@@ -63,22 +82,66 @@ func (b *Bot) CustomizePage() {
 	// vw, vh := w, 728
 	// b.page = b.page.MustSetViewport(display.ViewOffsetWidth, display.ViewOffsetHeight, 0.0, false)
 
-	b.setWindowAndViewport()
+	if err := b.setWindowAndViewport(); err != nil {
+		return fmt.Errorf("%w: %w", ErrWindowSetupFailed, err)
+	}
+
+	if b.responseCacheDir != "" && b.responseCache == nil {
+		cache, err := b.EnableResponseCache(b.responseCacheDir, b.responseCachePolicy)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrCreatePageFailed, err)
+		}
+
+		b.responseCache = cache
+	}
+
+	if err := b.startNetworkLogByOptions(); err != nil {
+		return fmt.Errorf("%w: %w", ErrCreatePageFailed, err)
+	}
+
+	if b.networkProfile != nil {
+		if err := b.EmulateNetwork(b.networkProfile); err != nil {
+			return fmt.Errorf("%w: cannot emulate network: %w", ErrCreatePageFailed, err)
+		}
+	}
 
 	b.isLaunched = true
+
+	return nil
 }
 
-func (b *Bot) setWindowAndViewport() {
+// keepNetworkEnabled enables the Network domain for the page's lifetime.
+// The domain state is shared by the page, so the listeners (network log, HAR, streams, WaitResponse...)
+// find it enabled and never disable it when they stop, which would break the others and drop EmulateNetwork.
+func (b *Bot) keepNetworkEnabled() {
+	b.page.EnableDomain(&proto.NetworkEnable{})
+}
+
+func (b *Bot) newPage() (*rod.Page, error) {
+	if b.stealthMode && !b.userMode {
+		return stealth.Page(b.browser)
+	}
+
+	return b.browser.Page(proto.TargetCreateTarget{})
+}
+
+func (b *Bot) setWindowAndViewport() error {
 	if b.windowMaximize {
-		b.page = b.page.MustWindowMaximize()
-		return
+		return b.page.SetWindow(&proto.BrowserBounds{
+			WindowState: proto.BrowserWindowStateMaximized,
+		})
 	}
 
 	if b.bounds != nil {
 		disp := b.bounds
-		b.page = b.page.MustSetWindow(disp.Left, disp.Top, disp.Width, disp.Height)
 
-		return
+		return b.page.SetWindow(&proto.BrowserBounds{
+			Left:        &disp.Left,
+			Top:         &disp.Top,
+			Width:       &disp.Width,
+			Height:      &disp.Height,
+			WindowState: proto.BrowserWindowStateNormal,
+		})
 	}
 
 	err := b.page.SetWindow(&proto.BrowserBounds{
@@ -86,10 +149,12 @@ func (b *Bot) setWindowAndViewport() {
 		WindowState: proto.BrowserWindowStateNormal,
 	})
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	_ = b.page.SetViewport(nil)
+
+	return nil
 }
 
 func (b *Bot) MustOpen(uri string) {
@@ -99,7 +164,12 @@ func (b *Bot) MustOpen(uri string) {
 		b.cookieFile != "" ||
 		len(b.copyAsCURLCookies) != 0
 
-	b.logger.Debug("open page", zap.Bool("cookies", withCookies))
+	b.logger.Debug("open page", zap.Bool("cookies", withCookies), zap.String("storage_state", b.storageStateFile))
+
+	if b.storageStateFile != "" {
+		b.pie(b.RestoreStorageState(uri))
+	}
+
 	// not with cookies, return
 	if !withCookies {
 		b.pie(b.Open(uri))
@@ -115,6 +185,7 @@ func (b *Bot) MustOpen(uri string) {
 // typically with following steps:
 //   - open it's domain `https://xxx.com`
 //   - load cookies
+//   - prune expired cookies and check required cookies, if enabled by WithPruneExpiredCookies/WithRequiredCookies
 //   - open uri
 func (b *Bot) OpenAndSetCookies(uri string, timeouts ...time.Duration) error {
 	up, err := url.Parse(uri)
@@ -132,11 +203,21 @@ func (b *Bot) OpenAndSetCookies(uri string, timeouts ...time.Duration) error {
 		b.logger.Sugar().Infof("use cookie file %s", b.cookieFile)
 	}
 
+	// a missing file is expected before the first dump, others like a wrong cookie key are returned.
 	nodes, err := b.LoadCookies(b.cookieFile)
 	if err != nil {
+		if !errors.Is(err, ErrMissingCookieFile) {
+			return err
+		}
+
 		b.logger.Info("cannot load cookies", zap.String("cookie", b.cookieFile), zap.Error(err))
 	}
 
+	nodes, err = b.checkLoadedCookies(nodes)
+	if err != nil {
+		return err
+	}
+
 	if len(nodes) != 0 {
 		b.logger.Sugar().Infof("total got cookie-nodes: %d", len(nodes))
 		// set cookies to browser/page
@@ -202,11 +283,11 @@ func (b *Bot) Eval(script string) (*proto.RuntimeRemoteObject, error) {
 func (b *Bot) WaitURLContains(str string, timeouts ...float64) error {
 	defer b.LogTimeSpent(time.Now())
 
-	timeout := FirstOrDefault(float64(MediumToSec), timeouts...)
+	timeout := FirstOrDefault(b.mediumTimeout.Seconds(), timeouts...)
 	script := fmt.Sprintf(`() => decodeURIComponent(window.location.href).includes("%s")`, str)
 
 	return rod.Try(func() {
-		b.page.Timeout(time.Second * time.Duration(timeout)).MustWait(script).CancelTimeout()
+		b.page.Timeout(secToDuration(timeout)).MustWait(script).CancelTimeout()
 	})
 }
 
@@ -226,7 +307,9 @@ func (b *Bot) MustWaitLoad() {
 
 // ActivatePage activates a page instead of current.
 func (b *Bot) ActivatePage(page *rod.Page) error {
-	b.prevPage, b.page = b.page, page
+	b.prevPage, b.page = b.page, b.bindContext(page)
+	b.keepNetworkEnabled()
+
 	_, err := b.page.Activate()
 
 	return err
@@ -264,7 +347,10 @@ func (b *Bot) ActivatePageByURLRegex(jsRegex string, retry int) error {
 	for i := 0; i < retry; i++ {
 		page, _ = b.browser.MustPages().FindByURL(jsRegex)
 		if page == nil {
-			SleepN(1.0)
+			if err := b.randSleep(1.0, 1.1); err != nil { //nolint:mnd
+				return err
+			}
+
 			continue
 		}
 
@@ -296,7 +382,9 @@ func (b *Bot) ActivateLastOpenedPage(pagesBefore rod.Pages, retry int) error {
 			break
 		}
 
-		SleepN(1.0)
+		if err := b.randSleep(1.0, 1.1); err != nil { //nolint:mnd
+			return err
+		}
 	}
 
 	if page == nil {
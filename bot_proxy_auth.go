@@ -0,0 +1,227 @@
+package wee
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+)
+
+// _proxyAuths are credentials of BrowserProxy by browser.
+var _proxyAuths sync.Map
+
+type proxyAuth struct {
+	username string
+	password string
+
+	mu sync.Mutex
+	// attempted are requests already answered, chrome asks again right away when credentials are wrong.
+	attempted map[proto.FetchRequestID]time.Time
+}
+
+// _authRetryWindow is how long an answered challenge is remembered,
+// a request not asked again within it got past the proxy, so it's forgotten.
+const _authRetryWindow = 30 * time.Second
+
+func newProxyAuth(username, password string) *proxyAuth {
+	return &proxyAuth{
+		username:  username,
+		password:  password,
+		attempted: make(map[proto.FetchRequestID]time.Time),
+	}
+}
+
+// WithProxyAuth answers proxy auth challenges with username and password,
+// it's for browsers launched with a proxy set elsewhere, e.g. by BrowserFlags("--proxy-server=host:port").
+//
+// Credentials of BrowserProxy("user:pass@host:port") are answered without it.
+// Challenges are answered by the bot's router, so only one bot of a browser can use it, see AddHijackRule.
+func WithProxyAuth(username, password string) BotOption {
+	return func(o *Bot) {
+		o.proxyAuth = newProxyAuth(username, password)
+	}
+}
+
+// startProxyAuth starts answering proxy auth challenges of WithProxyAuth or BrowserProxy.
+//
+// Challenges are answered by the Fetch domain, so it works in headless mode where extensions are not loaded.
+// The bot's router is started and kept running, so all requests are paused and continued by it.
+func (b *Bot) startProxyAuth() error {
+	auth := b.proxyAuth
+	if auth == nil {
+		v, ok := _proxyAuths.Load(b.browser)
+		if !ok {
+			return nil
+		}
+
+		auth, _ = v.(*proxyAuth)
+	}
+
+	return b.hijacker.setProxyAuth(b.browser, auth, b.logger)
+}
+
+func (r *hijackRouter) setProxyAuth(browser *rod.Browser, auth *proxyAuth, logger *zap.Logger) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.auth == auth {
+		return nil
+	}
+
+	if r.auth != nil {
+		r.stopAuth()
+		r.auth = auth
+		r.listenAuth(browser, logger)
+
+		return nil
+	}
+
+	r.auth = auth
+
+	var err error
+	if r.router != nil {
+		err = enableFetchAuth(browser, true)
+	} else {
+		err = r.start(browser)
+	}
+
+	if err != nil {
+		r.auth = nil
+		return err
+	}
+
+	r.listenAuth(browser, logger)
+
+	return nil
+}
+
+// clearProxyAuth stops answering proxy auth challenges,
+// the router is stopped when it has no rule, else Fetch is enabled again without auth handling.
+func (r *hijackRouter) clearProxyAuth(browser *rod.Browser) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.auth == nil {
+		return nil
+	}
+
+	r.stopAuth()
+	r.auth, r.stopAuth = nil, nil
+
+	if len(r.rules) == 0 {
+		return r.stopRouter()
+	}
+
+	if r.router == nil {
+		return nil
+	}
+
+	return enableFetchAuth(browser, false)
+}
+
+// listenAuth starts answering auth challenges of browser with r.auth, r.mu must be held.
+// Fetch.authRequired is a browser level event, it's the router's browser of the bot, see ErrBrowserHijacked.
+func (r *hijackRouter) listenAuth(browser *rod.Browser, logger *zap.Logger) {
+	auth := r.auth
+
+	// cancelled by clearProxyAuth, the listener must not outlive the bot.
+	ctx, cancel := context.WithCancel(context.Background())
+	brw := browser.Context(ctx)
+
+	wait := brw.EachEvent(func(e *proto.FetchAuthRequired) {
+		if err := auth.answer(e).Call(brw); err != nil {
+			logger.Warn("cannot answer auth challenge", zap.String("url", e.Request.URL), zap.Error(err))
+		}
+	})
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		wait()
+	}()
+
+	r.stopAuth = func() {
+		cancel()
+		<-done
+	}
+}
+
+// answer provides credentials to proxy challenges, and cancels it when they're refused.
+// Challenges of servers get the default behavior of chrome.
+func (a *proxyAuth) answer(e *proto.FetchAuthRequired) proto.FetchContinueWithAuth {
+	resp := &proto.FetchAuthChallengeResponse{Response: proto.FetchAuthChallengeResponseResponseDefault}
+
+	if e.AuthChallenge != nil && e.AuthChallenge.Source == proto.FetchAuthChallengeSourceProxy {
+		a.mu.Lock()
+
+		now := time.Now()
+		a.forget(now)
+
+		if _, ok := a.attempted[e.RequestID]; ok {
+			delete(a.attempted, e.RequestID)
+
+			resp.Response = proto.FetchAuthChallengeResponseResponseCancelAuth
+		} else {
+			a.attempted[e.RequestID] = now
+
+			resp.Response = proto.FetchAuthChallengeResponseResponseProvideCredentials
+			resp.Username = a.username
+			resp.Password = a.password
+		}
+
+		a.mu.Unlock()
+	}
+
+	return proto.FetchContinueWithAuth{RequestID: e.RequestID, AuthChallengeResponse: resp}
+}
+
+// forget removes requests not asked again within _authRetryWindow, they succeeded, a.mu must be held.
+// Fetch.authRequired has no event for success, and Network events are not sent to the browser session.
+func (a *proxyAuth) forget(now time.Time) {
+	for id, at := range a.attempted {
+		if now.Sub(at) > _authRetryWindow {
+			delete(a.attempted, id)
+		}
+	}
+}
+
+// enableFetchAuth enables the Fetch domain with or without auth handling,
+// the rod router enables it without, so it's called again with the same pattern.
+func enableFetchAuth(browser *rod.Browser, handle bool) error {
+	return proto.FetchEnable{
+		Patterns:           []*proto.FetchRequestPattern{{URLPattern: "*"}},
+		HandleAuthRequests: handle,
+	}.Call(browser)
+}
+
+// splitProxyAuth splits "[scheme://][user:pass@]host:port" into the server chrome accepts and the credentials.
+// Credentials can be url escaped, e.g. "p%40ss" for "p@ss".
+func splitProxyAuth(proxy string) (server, username, password string) {
+	scheme, rest := "", proxy
+	if i := strings.Index(proxy, "://"); i >= 0 {
+		scheme, rest = proxy[:i+len("://")], proxy[i+len("://"):]
+	}
+
+	i := strings.LastIndex(rest, "@")
+	if i < 0 {
+		return proxy, "", ""
+	}
+
+	username, password, _ = strings.Cut(rest[:i], ":")
+
+	if v, err := url.PathUnescape(username); err == nil {
+		username = v
+	}
+
+	if v, err := url.PathUnescape(password); err == nil {
+		password = v
+	}
+
+	return scheme + rest[i+1:], username, password
+}
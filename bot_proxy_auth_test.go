@@ -0,0 +1,122 @@
+package wee
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coghost/wee/fixtures"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProxyAuthSuite struct {
+	suite.Suite
+}
+
+func TestProxyAuth(t *testing.T) {
+	suite.Run(t, new(ProxyAuthSuite))
+}
+
+func (s *ProxyAuthSuite) TestSplitProxyAuth() {
+	tests := []struct {
+		proxy, server, username, password string
+	}{
+		{"127.0.0.1:8080", "127.0.0.1:8080", "", ""},
+		{"socks5://127.0.0.1:1080", "socks5://127.0.0.1:1080", "", ""},
+		{"user:pass@127.0.0.1:8080", "127.0.0.1:8080", "user", "pass"},
+		{"http://user:p%40ss%3A1@[::1]:8080", "http://[::1]:8080", "user", "p@ss:1"},
+		{"user:p@ss@proxy.example.com:8000", "proxy.example.com:8000", "user", "p@ss"},
+	}
+
+	for _, tt := range tests {
+		server, username, password := splitProxyAuth(tt.proxy)
+		s.Equal(tt.server, server, tt.proxy)
+		s.Equal(tt.username, username, tt.proxy)
+		s.Equal(tt.password, password, tt.proxy)
+	}
+}
+
+func (s *ProxyAuthSuite) TestAnswer() {
+	auth := newProxyAuth("user", "pass")
+
+	proxy := &proto.FetchAuthRequired{
+		RequestID:     "1",
+		AuthChallenge: &proto.FetchAuthChallenge{Source: proto.FetchAuthChallengeSourceProxy},
+	}
+
+	resp := auth.answer(proxy).AuthChallengeResponse
+	s.Equal(proto.FetchAuthChallengeResponseResponseProvideCredentials, resp.Response)
+	s.Equal("user", resp.Username)
+	s.Equal("pass", resp.Password)
+
+	resp = auth.answer(proxy).AuthChallengeResponse
+	s.Equal(proto.FetchAuthChallengeResponseResponseCancelAuth, resp.Response, "refused credentials are not sent again")
+
+	server := &proto.FetchAuthRequired{
+		RequestID:     "2",
+		AuthChallenge: &proto.FetchAuthChallenge{Source: proto.FetchAuthChallengeSourceServer},
+	}
+	s.Equal(proto.FetchAuthChallengeResponseResponseDefault, auth.answer(server).AuthChallengeResponse.Response)
+}
+
+func (s *ProxyAuthSuite) TestForget() {
+	auth := newProxyAuth("user", "pass")
+
+	proxy := &proto.FetchAuthRequired{
+		RequestID:     "1",
+		AuthChallenge: &proto.FetchAuthChallenge{Source: proto.FetchAuthChallengeSourceProxy},
+	}
+
+	auth.answer(proxy)
+	auth.attempted[proxy.RequestID] = time.Now().Add(-_authRetryWindow - time.Second)
+
+	auth.answer(&proto.FetchAuthRequired{RequestID: "2", AuthChallenge: proxy.AuthChallenge})
+	s.NotContains(auth.attempted, proxy.RequestID, "succeeded requests are forgotten")
+	s.Contains(auth.attempted, proto.FetchRequestID("2"))
+}
+
+func (s *ProxyAuthSuite) TestBot() {
+	ts := fixtures.NewTestServer()
+	defer ts.Close()
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:p@ss"))
+		if r.Header.Get("Proxy-Authorization") != want {
+			w.Header().Set("Proxy-Authenticate", `Basic realm="wee"`)
+			w.WriteHeader(http.StatusProxyAuthRequired)
+
+			return
+		}
+
+		resp, err := http.Get(r.URL.String()) //nolint:noctx
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}))
+	defer proxy.Close()
+
+	l, brw, err := NewBrowserE(
+		BrowserHeadless(true),
+		BrowserProxy("user:p%40ss@"+proxy.Listener.Addr().String()),
+		// chrome never uses proxies for loopback by default.
+		BrowserFlags("proxy-bypass-list=<-loopback>"),
+	)
+	s.Require().NoError(err)
+
+	bot, err := NewBotE(Launcher(l), Browser(brw))
+	s.Require().NoError(err)
+
+	defer bot.Cleanup()
+
+	bot.MustOpen(ts.URL + "/hellowee")
+	s.Contains(bot.page.MustElement("body").MustText(), "hello")
+}
@@ -0,0 +1,230 @@
+package wee
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+var (
+	ErrWaitResponseTimeout = errors.New("no matched response in time")
+	ErrResponseFailed      = errors.New("matched request failed")
+)
+
+// NetworkResponse is a response captured by WaitResponse.
+type NetworkResponse struct {
+	URL          string
+	Method       string
+	Status       int
+	Headers      http.Header
+	ResourceType proto.NetworkResourceType
+	Body         []byte
+}
+
+// JSON unmarshals the body into v.
+func (r *NetworkResponse) JSON(v any) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+type WaitResponseOptions struct {
+	timeout       time.Duration
+	method        string
+	resourceTypes []proto.NetworkResourceType
+}
+
+type WaitResponseOptionFunc func(o *WaitResponseOptions)
+
+func bindWaitResponseOptions(opt *WaitResponseOptions, opts ...WaitResponseOptionFunc) {
+	for _, f := range opts {
+		f(opt)
+	}
+}
+
+// WithResponseTimeout sets the timeout in seconds, default is bot's medium timeout.
+func WithResponseTimeout(t float64) WaitResponseOptionFunc {
+	return func(o *WaitResponseOptions) {
+		o.timeout = secToDuration(t)
+	}
+}
+
+// WithResponseMethod only matches requests with method, e.g. "POST".
+func WithResponseMethod(method string) WaitResponseOptionFunc {
+	return func(o *WaitResponseOptions) {
+		o.method = strings.ToUpper(method)
+	}
+}
+
+// WithResponseTypes sets resource types to match, default is XHR and Fetch.
+func WithResponseTypes(types ...proto.NetworkResourceType) WaitResponseOptionFunc {
+	return func(o *WaitResponseOptions) {
+		o.resourceTypes = types
+	}
+}
+
+// WaitResponse arms a matcher for pattern, runs trigger, and returns the first matched response with its body.
+//
+// pattern is the same as proto.FetchRequestPattern.URLPattern, e.g. "*api/search*".
+// The matcher is armed before trigger runs, so a response triggered immediately is not missed.
+// trigger can be nil, to wait for a response caused by something already running.
+//
+// Usage:
+//
+//	resp, err := bot.WaitResponse("*api/search*", func() error {
+//	    return bot.Click(`button.search`)
+//	}, wee.WithResponseTimeout(10))
+//
+//	var data SearchResult
+//	err = resp.JSON(&data)
+//
+// Returns:
+//   - ErrWaitResponseTimeout if nothing matched before timeout or the bot's context is done.
+//   - ErrResponseFailed if the matched request failed to load.
+//   - the error of trigger as is.
+func (b *Bot) WaitResponse(pattern string, trigger func() error, opts ...WaitResponseOptionFunc) (*NetworkResponse, error) {
+	opt := &WaitResponseOptions{
+		timeout:       b.mediumTimeout,
+		resourceTypes: []proto.NetworkResourceType{proto.NetworkResourceTypeXHR, proto.NetworkResourceTypeFetch},
+	}
+	bindWaitResponseOptions(opt, opts...)
+
+	reg := regexp.MustCompile(proto.PatternToReg(StrAorB(pattern, "*")))
+
+	ctx, cancel := context.WithTimeout(b.Context(), opt.timeout)
+	defer cancel()
+
+	page := b.page.Context(ctx)
+
+	var (
+		methods  = make(map[proto.NetworkRequestID]string)
+		matched  *NetworkResponse
+		reqID    proto.NetworkRequestID
+		finished bool
+		result   error
+	)
+
+	wait := page.EachEvent(
+		func(e *proto.NetworkRequestWillBeSent) {
+			methods[e.RequestID] = e.Request.Method
+		},
+		func(e *proto.NetworkResponseReceived) {
+			if matched != nil || !reg.MatchString(e.Response.URL) || !opt.matchType(e.Type) {
+				return
+			}
+
+			method := methods[e.RequestID]
+			if opt.method != "" && method != opt.method {
+				return
+			}
+
+			reqID = e.RequestID
+			matched = &NetworkResponse{
+				URL:          e.Response.URL,
+				Method:       method,
+				Status:       e.Response.Status,
+				Headers:      toHTTPHeader(e.Response.Headers),
+				ResourceType: e.Type,
+			}
+		},
+		func(e *proto.NetworkLoadingFinished) bool {
+			if matched == nil || e.RequestID != reqID {
+				return false
+			}
+
+			// read body before the wait ends, the page context is canceled after that.
+			matched.Body, result = getResponseBody(page, reqID)
+			finished = true
+
+			return true
+		},
+		func(e *proto.NetworkLoadingFailed) bool {
+			if matched == nil || e.RequestID != reqID {
+				return false
+			}
+
+			result = fmt.Errorf("%w: %s: %s", ErrResponseFailed, matched.URL, e.ErrorText)
+
+			return true
+		},
+	)
+
+	if trigger != nil {
+		if err := trigger(); err != nil {
+			cancel()
+			wait()
+
+			return nil, err
+		}
+	}
+
+	wait()
+
+	if result != nil {
+		return nil, result
+	}
+
+	if !finished {
+		err := fmt.Errorf("%w: %s", ErrWaitResponseTimeout, pattern)
+		if ctx.Err() != nil {
+			err = fmt.Errorf("%w: %w", err, ctx.Err())
+		}
+
+		return nil, err
+	}
+
+	return matched, nil
+}
+
+func (b *Bot) MustWaitResponse(pattern string, trigger func() error, opts ...WaitResponseOptionFunc) *NetworkResponse {
+	resp, err := b.WaitResponse(pattern, trigger, opts...)
+	b.pie(err)
+
+	return resp
+}
+
+func (o *WaitResponseOptions) matchType(typ proto.NetworkResourceType) bool {
+	if len(o.resourceTypes) == 0 {
+		return true
+	}
+
+	for _, t := range o.resourceTypes {
+		if t == typ {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getResponseBody(client proto.Client, reqID proto.NetworkRequestID) ([]byte, error) {
+	res, err := proto.NetworkGetResponseBody{RequestID: reqID}.Call(client)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get response body: %w", err)
+	}
+
+	if !res.Base64Encoded {
+		return []byte(res.Body), nil
+	}
+
+	return base64.StdEncoding.DecodeString(res.Body)
+}
+
+// toHTTPHeader converts devtools headers, multiple values of one header are joined by "\n".
+func toHTTPHeader(headers proto.NetworkHeaders) http.Header {
+	h := http.Header{}
+
+	for k, v := range headers {
+		for _, value := range strings.Split(v.Str(), "\n") {
+			h.Add(k, value)
+		}
+	}
+
+	return h
+}
@@ -0,0 +1,497 @@
+package wee
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/gookit/goutil/fsutil"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultCacheTTL          = 24 * time.Hour
+	DefaultCacheMaxEntrySize = 5 << 20
+	DefaultCacheMaxSize      = 512 << 20
+
+	_cacheFileExt        = ".json"
+	_cacheDirPermissions = 0o700
+)
+
+var ErrInvalidCacheDir = errors.New("invalid response cache dir")
+
+// CachePolicy decides which responses WithResponseCache stores and how long they're served.
+type CachePolicy struct {
+	// TTL of a stored response, 0 never expires.
+	TTL time.Duration
+	// TypeTTL overrides TTL of one resource type, e.g. keep images for a week.
+	TypeTTL map[proto.NetworkResourceType]time.Duration
+	// ResourceTypes are cached, other types always go to network.
+	ResourceTypes []proto.NetworkResourceType
+	// KeyHeaders are request headers in the cache key besides method and url, e.g. "Accept-Language".
+	KeyHeaders []string
+	// MaxEntrySize in bytes, bigger responses are not stored, 0 is no limit.
+	MaxEntrySize int
+	// MaxSize in bytes of the cache dir, the oldest responses are evicted when exceeded, 0 is no limit.
+	MaxSize int64
+}
+
+// NewCachePolicyDefault caches static resources, HTML and XHR are always fresh.
+//
+//	@return *CachePolicy {24h, image/script/stylesheet/font, 5MB per entry, 512MB total}
+func NewCachePolicyDefault() *CachePolicy {
+	return &CachePolicy{
+		TTL: DefaultCacheTTL,
+		ResourceTypes: []proto.NetworkResourceType{
+			proto.NetworkResourceTypeImage,
+			proto.NetworkResourceTypeScript,
+			proto.NetworkResourceTypeStylesheet,
+			proto.NetworkResourceTypeFont,
+		},
+		MaxEntrySize: DefaultCacheMaxEntrySize,
+		MaxSize:      DefaultCacheMaxSize,
+	}
+}
+
+// WithResponseCache enables the on-disk response cache in dir when the page is created,
+// a nil policy is NewCachePolicyDefault, see EnableResponseCache.
+func WithResponseCache(dir string, policy *CachePolicy) BotOption {
+	return func(o *Bot) {
+		o.responseCacheDir = dir
+		o.responseCachePolicy = policy
+	}
+}
+
+// ResponseCache stores GET responses of the bot's page on disk, and serves them back by interception.
+type ResponseCache struct {
+	dir    string
+	policy *CachePolicy
+	logger *zap.Logger
+	handle *HijackRuleHandle
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu      sync.Mutex
+	pending map[proto.NetworkRequestID]*cachePending
+	// keys are computed by serve for requests going to network, by network id, they're stored with them by onFinished.
+	// The key is from the paused request, the headers of Network events are not the same.
+	keys map[proto.NetworkRequestID]string
+	// frames are of the bot's page, the router is browser level and pauses requests of other pages too.
+	frames map[proto.PageFrameID]struct{}
+	hits   int
+	misses int
+	// size is bytes of stored responses, the dir is only scanned when it exceeds MaxSize.
+	size int64
+}
+
+type cachePending struct {
+	resourceType proto.NetworkResourceType
+	resp         *proto.NetworkResponse
+}
+
+// cachedResponse is the file content of one stored response.
+type cachedResponse struct {
+	URL          string                    `json:"url"`
+	Status       int                       `json:"status"`
+	StatusText   string                    `json:"statusText"`
+	Headers      []*HARNameValue           `json:"headers"`
+	ResourceType proto.NetworkResourceType `json:"resourceType"`
+	StoredAt     time.Time                 `json:"storedAt"`
+	Body         []byte                    `json:"body"`
+}
+
+// EnableResponseCache serves GET responses from dir when stored by a previous run and not expired,
+// others go to network and successful ones (status 200) are stored for next runs.
+//
+// It's meant for developing scripts which open the same pages again and again.
+// The cache key is method, url (fragment ignored) and policy.KeyHeaders,
+// response headers like Cache-Control are ignored, and Set-Cookie is never stored.
+//
+// Responses are stored from the network events of the bot's page,
+// so the browser still does the real request with its own cookies, requests of other pages are not cached.
+// Cached responses are served by a rule of the bot's router, mocks with higher priority run first.
+//
+// Usage:
+//
+//	policy := wee.NewCachePolicyDefault()
+//	policy.TypeTTL = map[proto.NetworkResourceType]time.Duration{proto.NetworkResourceTypeImage: 7 * 24 * time.Hour}
+//
+//	cache, err := bot.EnableResponseCache("/tmp/wee-cache", policy)
+//	defer cache.Close()
+func (b *Bot) EnableResponseCache(dir string, policy *CachePolicy) (*ResponseCache, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("%w: empty dir", ErrInvalidCacheDir)
+	}
+
+	if err := fsutil.Mkdir(dir, _cacheDirPermissions); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCacheDir, err)
+	}
+
+	if policy == nil {
+		policy = NewCachePolicyDefault()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := &ResponseCache{
+		dir:     dir,
+		policy:  policy,
+		logger:  b.logger,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		pending: make(map[proto.NetworkRequestID]*cachePending),
+		keys:    make(map[proto.NetworkRequestID]string),
+		frames:  map[proto.PageFrameID]struct{}{b.page.FrameID: {}},
+	}
+
+	if _, size, err := c.scan(); err == nil {
+		c.size = size
+	}
+
+	handle, err := b.AddHijackRule(HijackRule{
+		Match: func(ctx *rod.Hijack) bool {
+			return ctx.Request.Method() == "GET" && c.policy.caches(ctx.Request.Type())
+		},
+		Handler: c.serve,
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	c.handle = handle
+
+	page := b.page.Context(ctx)
+
+	wait := page.EachEvent(
+		c.onRequest,
+		c.onResponse,
+		func(e *proto.NetworkLoadingFinished) {
+			c.onFinished(page, e)
+		},
+		c.onFailed,
+	)
+
+	go func() {
+		defer close(c.done)
+		wait()
+	}()
+
+	return c, nil
+}
+
+func (b *Bot) MustEnableResponseCache(dir string, policy *CachePolicy) *ResponseCache {
+	c, err := b.EnableResponseCache(dir, policy)
+	b.pie(err)
+
+	return c
+}
+
+// Close stops serving and storing responses, stored files are kept.
+func (c *ResponseCache) Close() error {
+	if c == nil {
+		return nil
+	}
+
+	c.cancel()
+	<-c.done
+
+	return c.handle.Remove()
+}
+
+// Stats returns how many requests are served from cache and how many went to network.
+func (c *ResponseCache) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.hits, c.misses
+}
+
+func (c *ResponseCache) serve(ctx *rod.Hijack) {
+	e := pausedEvent(ctx)
+
+	// requests of other pages, or without Network events to store them, go to network untouched.
+	if e.NetworkID == "" || !c.owns(e.FrameID) {
+		ctx.Skip = true
+		return
+	}
+
+	key := c.policy.key("GET", ctx.Request.URL().String(), toHTTPHeader(ctx.Request.Headers()))
+
+	cached := c.load(key, ctx.Request.Type())
+
+	c.mu.Lock()
+	if cached == nil {
+		c.misses++
+		// a redirect reuses the network id, the key of the last hop is stored.
+		c.keys[e.NetworkID] = key
+	} else {
+		c.hits++
+		delete(c.keys, e.NetworkID)
+	}
+	c.mu.Unlock()
+
+	if cached == nil {
+		ctx.Skip = true
+		return
+	}
+
+	payload := ctx.Response.Payload()
+	payload.ResponseCode = cached.Status
+	payload.ResponsePhrase = cached.StatusText
+	payload.ResponseHeaders = harReplayHeaders(cached.Headers)
+	payload.Body = cached.Body
+}
+
+func (c *ResponseCache) onRequest(e *proto.NetworkRequestWillBeSent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.frames[e.FrameID] = struct{}{}
+
+	// a redirect reuses the request id, only the final response is stored.
+	delete(c.pending, e.RequestID)
+
+	if e.Request.Method != "GET" || !c.policy.caches(e.Type) {
+		return
+	}
+
+	c.pending[e.RequestID] = &cachePending{
+		resourceType: e.Type,
+	}
+}
+
+func (c *ResponseCache) onResponse(e *proto.NetworkResponseReceived) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if p, ok := c.pending[e.RequestID]; ok {
+		p.resp = e.Response
+	}
+}
+
+func (c *ResponseCache) onFailed(e *proto.NetworkLoadingFailed) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.takeKey(e.RequestID)
+	delete(c.pending, e.RequestID)
+}
+
+func (c *ResponseCache) onFinished(client proto.Client, e *proto.NetworkLoadingFinished) {
+	c.mu.Lock()
+
+	p, ok := c.pending[e.RequestID]
+	delete(c.pending, e.RequestID)
+
+	key := c.takeKey(e.RequestID)
+
+	c.mu.Unlock()
+
+	// no key when it's served from cache, nothing new to store.
+	if !ok || key == "" || p.resp == nil || p.resp.Status != 200 { //nolint:mnd
+		return
+	}
+
+	body, err := getResponseBody(client, e.RequestID)
+	if err != nil {
+		c.logger.Debug("cannot cache response", zap.String("url", p.resp.URL), zap.Error(err))
+		return
+	}
+
+	if c.policy.MaxEntrySize > 0 && len(body) > c.policy.MaxEntrySize {
+		return
+	}
+
+	headers := toHTTPHeader(p.resp.Headers)
+	headers.Del("Set-Cookie")
+
+	err = c.store(key, &cachedResponse{
+		URL:          p.resp.URL,
+		Status:       p.resp.Status,
+		StatusText:   p.resp.StatusText,
+		Headers:      harNameValues(headers),
+		ResourceType: p.resourceType,
+		StoredAt:     time.Now(),
+		Body:         body,
+	})
+	if err != nil {
+		c.logger.Warn("cannot store response", zap.String("url", p.resp.URL), zap.Error(err))
+	}
+}
+
+// load returns the stored response of key, or nil if not stored or expired.
+func (c *ResponseCache) load(key string, typ proto.NetworkResourceType) *cachedResponse {
+	raw, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil
+	}
+
+	var cached cachedResponse
+	if err := json.Unmarshal(raw, &cached); err != nil {
+		return nil
+	}
+
+	if ttl := c.policy.ttl(typ); ttl > 0 && time.Since(cached.StoredAt) > ttl {
+		return nil
+	}
+
+	return &cached
+}
+
+// takeKey removes and returns the key computed by serve for the request, c.mu must be held.
+func (c *ResponseCache) takeKey(id proto.NetworkRequestID) string {
+	key := c.keys[id]
+	delete(c.keys, id)
+
+	return key
+}
+
+// owns reports whether the frame is of the bot's page.
+func (c *ResponseCache) owns(frame proto.PageFrameID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.frames[frame]
+
+	return ok
+}
+
+func (c *ResponseCache) store(key string, cached *cachedResponse) error {
+	content, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+
+	var replaced int64
+	if info, err := os.Stat(c.path(key)); err == nil {
+		replaced = info.Size()
+	}
+
+	if err := writeSecretFile(c.path(key), content); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.size += int64(len(content)) - replaced
+	exceeded := c.policy.MaxSize > 0 && c.size > c.policy.MaxSize
+	c.mu.Unlock()
+
+	if !exceeded {
+		return nil
+	}
+
+	return c.evict()
+}
+
+// evict removes the oldest responses until the cache dir fits in MaxSize.
+func (c *ResponseCache) evict() error {
+	if c.policy.MaxSize <= 0 {
+		return nil
+	}
+
+	infos, total, err := c.scan()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		c.mu.Lock()
+		c.size = total
+		c.mu.Unlock()
+	}()
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ModTime().Before(infos[j].ModTime())
+	})
+
+	for _, info := range infos {
+		if total <= c.policy.MaxSize {
+			break
+		}
+
+		if err := os.Remove(filepath.Join(c.dir, info.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		total -= info.Size()
+	}
+
+	return nil
+}
+
+// scan returns stored responses and their total size.
+func (c *ResponseCache) scan() ([]os.FileInfo, int64, error) {
+	files, err := filepath.Glob(filepath.Join(c.dir, "*"+_cacheFileExt))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		infos []os.FileInfo
+		total int64
+	)
+
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			continue
+		}
+
+		infos = append(infos, info)
+		total += info.Size()
+	}
+
+	return infos, total, nil
+}
+
+func (c *ResponseCache) path(key string) string {
+	return filepath.Join(c.dir, key+_cacheFileExt)
+}
+
+func (p *CachePolicy) caches(typ proto.NetworkResourceType) bool {
+	for _, t := range p.ResourceTypes {
+		if t == typ {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *CachePolicy) ttl(typ proto.NetworkResourceType) time.Duration {
+	if ttl, ok := p.TypeTTL[typ]; ok {
+		return ttl
+	}
+
+	return p.TTL
+}
+
+// key hashes method, url without fragment and values of KeyHeaders.
+func (p *CachePolicy) key(method, uri string, headers http.Header) string {
+	uri, _, _ = strings.Cut(uri, "#")
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", strings.ToUpper(method), uri)
+
+	names := append([]string(nil), p.KeyHeaders...)
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(h, "%s: %s\n", strings.ToLower(name), strings.Join(headers.Values(name), ","))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
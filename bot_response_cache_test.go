@@ -0,0 +1,142 @@
+package wee
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coghost/wee/fixtures"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type ResponseCacheSuite struct {
+	suite.Suite
+}
+
+func TestResponseCache(t *testing.T) {
+	suite.Run(t, new(ResponseCacheSuite))
+}
+
+func (s *ResponseCacheSuite) newCache(policy *CachePolicy) *ResponseCache {
+	return &ResponseCache{dir: s.T().TempDir(), policy: policy, logger: zap.NewNop()}
+}
+
+func (s *ResponseCacheSuite) TestKey() {
+	p := &CachePolicy{KeyHeaders: []string{"accept-language"}}
+
+	en := http.Header{"Accept-Language": {"en"}}
+	de := http.Header{"Accept-Language": {"de"}}
+
+	s.Equal(p.key("GET", "http://a.com/x.js#frag", en), p.key("get", "http://a.com/x.js", en))
+	s.NotEqual(p.key("GET", "http://a.com/x.js", en), p.key("GET", "http://a.com/x.js", de))
+	s.NotEqual(p.key("GET", "http://a.com/x.js", en), p.key("GET", "http://a.com/x.js?v=2", en))
+
+	noHeaders := &CachePolicy{}
+	s.Equal(noHeaders.key("GET", "http://a.com/x.js", en), noHeaders.key("GET", "http://a.com/x.js", de))
+}
+
+func (s *ResponseCacheSuite) TestTTL() {
+	c := s.newCache(&CachePolicy{
+		TTL:     time.Hour,
+		TypeTTL: map[proto.NetworkResourceType]time.Duration{proto.NetworkResourceTypeImage: 0},
+	})
+
+	old := &cachedResponse{Status: 200, StoredAt: time.Now().Add(-2 * time.Hour), Body: []byte("x")}
+	s.Require().NoError(c.store("old", old))
+
+	s.Nil(c.load("old", proto.NetworkResourceTypeScript), "expired after TTL")
+	s.NotNil(c.load("old", proto.NetworkResourceTypeImage), "type TTL 0 never expires")
+	s.Nil(c.load("missing", proto.NetworkResourceTypeScript))
+
+	got := c.load("old", proto.NetworkResourceTypeImage)
+	s.Equal([]byte("x"), got.Body)
+}
+
+func (s *ResponseCacheSuite) TestEvict() {
+	c := s.newCache(&CachePolicy{})
+
+	body := make([]byte, 1000)
+	for i, key := range []string{"a", "b", "c"} {
+		s.Require().NoError(c.store(key, &cachedResponse{Status: 200, StoredAt: time.Now(), Body: body}))
+
+		// make mod time distinct and ordered.
+		mt := time.Now().Add(time.Duration(i-10) * time.Minute)
+		s.Require().NoError(os.Chtimes(c.path(key), mt, mt))
+	}
+
+	info, err := os.Stat(c.path("a"))
+	s.Require().NoError(err)
+
+	c.policy.MaxSize = 2 * info.Size()
+	s.Require().NoError(c.evict())
+
+	s.NoFileExists(c.path("a"), "oldest is evicted")
+	s.FileExists(c.path("b"))
+	s.FileExists(c.path("c"))
+	s.Equal(2*info.Size(), c.size)
+
+	s.Require().NoError(c.store("d", &cachedResponse{Status: 200, StoredAt: time.Now(), Body: body}))
+	s.NoFileExists(c.path("b"), "evicted by store when size is exceeded")
+	s.FileExists(c.path("d"))
+}
+
+func (s *ResponseCacheSuite) TestTakeKey() {
+	c := s.newCache(&CachePolicy{})
+	c.keys = map[proto.NetworkRequestID]string{"1": "k1", "2": "k2"}
+
+	s.Equal("k2", c.takeKey("2"))
+	s.Empty(c.takeKey("2"), "taken once")
+	s.Empty(c.takeKey("3"), "served from cache")
+	s.Equal(map[proto.NetworkRequestID]string{"1": "k1"}, c.keys)
+}
+
+func (s *ResponseCacheSuite) TestOwns() {
+	c := s.newCache(&CachePolicy{})
+	c.pending = make(map[proto.NetworkRequestID]*cachePending)
+	c.frames = map[proto.PageFrameID]struct{}{"main": {}}
+
+	s.True(c.owns("main"))
+	s.False(c.owns("other-tab"))
+
+	c.onRequest(&proto.NetworkRequestWillBeSent{
+		RequestID: "1", FrameID: "iframe", Request: &proto.NetworkRequest{URL: "http://a.com/x.js", Method: "GET"},
+	})
+	s.True(c.owns("iframe"), "frames of the page's network events")
+}
+
+func (s *ResponseCacheSuite) TestBot() {
+	ts := fixtures.NewTestServer()
+	defer ts.Close()
+
+	dir := filepath.Join(s.T().TempDir(), "cache")
+	policy := &CachePolicy{ResourceTypes: []proto.NetworkResourceType{proto.NetworkResourceTypeScript}}
+
+	first := NewBotHeadless(WithResponseCache(dir, policy))
+	first.MustOpen(ts.URL + "/hijack_test?resource=cache")
+	first.page.MustWaitRequestIdle()
+
+	hits, misses := first.responseCache.Stats()
+	s.Equal(0, hits)
+	s.Equal(2, misses, "both scripts go to network")
+
+	s.Require().NoError(first.responseCache.Close())
+	first.Cleanup()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+_cacheFileExt))
+	s.Require().NoError(err)
+	s.Len(files, 2, "only scripts are stored")
+
+	second := NewBotHeadless(WithResponseCache(dir, policy))
+	defer second.Cleanup()
+
+	second.MustOpen(ts.URL + "/hijack_test?resource=cache")
+	second.page.MustWaitRequestIdle()
+
+	hits, misses = second.responseCache.Stats()
+	s.Equal(2, hits)
+	s.Equal(0, misses)
+}
@@ -0,0 +1,68 @@
+package wee
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/coghost/wee/fixtures"
+	"github.com/stretchr/testify/suite"
+)
+
+type BotResponseSuite struct {
+	suite.Suite
+	ts  *httptest.Server
+	bot *Bot
+}
+
+func TestBotResponse(t *testing.T) {
+	suite.Run(t, new(BotResponseSuite))
+}
+
+func (s *BotResponseSuite) SetupSuite() {
+	s.ts = fixtures.NewTestServer()
+	s.bot = NewBotHeadless()
+	s.bot.MustOpen(s.ts.URL + "/hellowee")
+}
+
+func (s *BotResponseSuite) TearDownSuite() {
+	s.bot.Cleanup()
+	s.ts.Close()
+}
+
+func (s *BotResponseSuite) fetch(uri string) func() error {
+	return func() error {
+		_, err := s.bot.page.Eval(`(u) => { fetch(u) }`, uri)
+		return err
+	}
+}
+
+func (s *BotResponseSuite) TestWaitResponse() {
+	resp, err := s.bot.WaitResponse("*api/data*", s.fetch("/api/data"))
+	s.Require().NoError(err)
+
+	s.Equal(200, resp.Status)
+	s.Equal("GET", resp.Method)
+	s.Contains(resp.Headers.Get("Content-Type"), "application/json")
+
+	var data map[string]string
+	s.Require().NoError(resp.JSON(&data))
+	s.Equal("XHR response", data["message"])
+}
+
+func (s *BotResponseSuite) TestTimeout() {
+	_, err := s.bot.WaitResponse("*api/data*", s.fetch("/test.css"), WithResponseTimeout(1))
+	s.ErrorIs(err, ErrWaitResponseTimeout)
+	s.ErrorIs(err, context.DeadlineExceeded)
+
+	_, err = s.bot.WaitResponse("*api/data*", s.fetch("/api/data"), WithResponseTimeout(1), WithResponseMethod("POST"))
+	s.ErrorIs(err, ErrWaitResponseTimeout, "method not matched")
+}
+
+func (s *BotResponseSuite) TestTriggerError() {
+	errTrigger := errors.New("cannot click")
+
+	_, err := s.bot.WaitResponse("*api/data*", func() error { return errTrigger })
+	s.ErrorIs(err, errTrigger)
+}
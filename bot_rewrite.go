@@ -0,0 +1,252 @@
+package wee
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+var ErrInvalidRewriteRule = errors.New("invalid rewrite rule")
+
+// RewriteRule changes matched requests before they're sent, see AddRewriteRule.
+type RewriteRule struct {
+	// Pattern is the same as HijackRule.Pattern, "" matches all.
+	Pattern string
+	// Regex is an optional regexp the url must match, it's also used by URL.
+	Regex string
+	// ResourceType limits the rule to one resource type, "" matches all.
+	ResourceType proto.NetworkResourceType
+	// Priority, rules with higher priority apply first, so rules with lower priority see their changes.
+	Priority int
+
+	// SetHeaders adds or replaces request headers, e.g. {"Authorization": "Bearer xxx"}.
+	SetHeaders map[string]string
+	// RemoveHeaders are removed, case-insensitive.
+	RemoveHeaders []string
+	// StripQuery removes query params by name or glob, e.g. "utm_*", "fbclid".
+	StripQuery []string
+	// URL replaces the url, with Regex it's the replacement of regexp.ReplaceAllString, e.g. "http://localhost:8080/$1".
+	URL string
+	// Method overrides the method if not empty.
+	Method string
+	// Body overrides the post data if not nil.
+	Body []byte
+}
+
+type rewriteRule struct {
+	RewriteRule
+	reg *regexp.Regexp
+}
+
+// AddRewriteRule adds declarative rules to change requests: headers, url, method and body.
+//
+// Rewrite rules are matched like hijack rules, all matched ones apply in priority order before other rules run,
+// and the request is sent with the changes when it's continued, see AddHijackRule.
+// Changes are not observable by the page, and rules of AddHijackRule still match the original url.
+//
+// Usage:
+//
+//	// inject a token on one host
+//	bot.MustAddRewriteRule(wee.RewriteRule{
+//	    Pattern:    "*://api.example.com/*",
+//	    SetHeaders: map[string]string{"Authorization": "Bearer " + token},
+//	})
+//
+//	// strip tracking params
+//	bot.MustAddRewriteRule(wee.RewriteRule{StripQuery: []string{"utm_*", "fbclid"}})
+//
+//	// load a CDN from a local mirror
+//	bot.MustAddRewriteRule(wee.RewriteRule{
+//	    Regex: `^https://cdn\.example\.com/(.*)$`,
+//	    URL:   "http://127.0.0.1:8080/$1",
+//	})
+func (b *Bot) AddRewriteRule(rules ...RewriteRule) (*HijackRuleHandle, error) {
+	handle := &HijackRuleHandle{router: b.hijacker}
+
+	for _, rule := range rules {
+		rr, err := newRewriteRule(rule)
+		if err != nil {
+			_ = handle.Remove()
+			return nil, err
+		}
+
+		hr := HijackRule{
+			Pattern:      rule.Pattern,
+			ResourceType: rule.ResourceType,
+			Priority:     rule.Priority,
+			Handler: func(ctx *rod.Hijack) {
+				rr.apply(ctx.Request.Req(), continuePayload(ctx))
+			},
+		}
+
+		if rr.reg != nil {
+			hr.Match = func(ctx *rod.Hijack) bool {
+				return rr.reg.MatchString(ctx.Request.URL().String())
+			}
+		}
+
+		id, err := b.hijacker.add(b.browser, hr, true)
+		if err != nil {
+			_ = handle.Remove()
+			return nil, err
+		}
+
+		handle.ids = append(handle.ids, id)
+	}
+
+	return handle, nil
+}
+
+func (b *Bot) MustAddRewriteRule(rules ...RewriteRule) *HijackRuleHandle {
+	h, err := b.AddRewriteRule(rules...)
+	b.pie(err)
+
+	return h
+}
+
+func newRewriteRule(rule RewriteRule) (*rewriteRule, error) {
+	rr := &rewriteRule{RewriteRule: rule}
+
+	if rule.Regex != "" {
+		reg, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRewriteRule, err)
+		}
+
+		rr.reg = reg
+	}
+
+	for _, ptn := range rule.StripQuery {
+		if _, err := path.Match(ptn, ""); err != nil {
+			return nil, fmt.Errorf("%w: strip query %q: %w", ErrInvalidRewriteRule, ptn, err)
+		}
+	}
+
+	return rr, nil
+}
+
+// apply records the changes into cq, and changes req as well,
+// so handlers loading the response by themselves see the rewritten request.
+func (r *rewriteRule) apply(req *http.Request, cq *proto.FetchContinueRequest) {
+	if len(r.SetHeaders) != 0 || len(r.RemoveHeaders) != 0 {
+		for _, name := range r.RemoveHeaders {
+			delHeader(req.Header, name)
+		}
+
+		for name, value := range r.SetHeaders {
+			delHeader(req.Header, name)
+			req.Header.Set(name, value)
+		}
+
+		cq.Headers = fetchHeaderEntries(req.Header)
+	}
+
+	if uri := r.rewriteURL(req.URL.String()); uri != req.URL.String() {
+		if u, err := url.Parse(uri); err == nil {
+			req.URL, req.Host = u, u.Host
+			cq.URL = uri
+		}
+	}
+
+	if r.Method != "" {
+		req.Method = r.Method
+		cq.Method = r.Method
+	}
+
+	if r.Body != nil {
+		req.Body = io.NopCloser(bytes.NewReader(r.Body))
+		req.ContentLength = int64(len(r.Body))
+		cq.PostData = r.Body
+	}
+}
+
+func (r *rewriteRule) rewriteURL(uri string) string {
+	switch {
+	case r.URL != "" && r.reg != nil:
+		uri = r.reg.ReplaceAllString(uri, r.URL)
+	case r.URL != "":
+		uri = r.URL
+	}
+
+	if len(r.StripQuery) == 0 {
+		return uri
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil || u.RawQuery == "" {
+		return uri
+	}
+
+	var (
+		kept     []string
+		stripped bool
+	)
+
+	for _, pair := range strings.Split(u.RawQuery, "&") {
+		name, _, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+
+		if r.strips(name) {
+			stripped = true
+			continue
+		}
+
+		kept = append(kept, pair)
+	}
+
+	// keep the raw query as is when nothing is stripped, encoding may differ.
+	if !stripped {
+		return uri
+	}
+
+	u.RawQuery = strings.Join(kept, "&")
+
+	return u.String()
+}
+
+func (r *rewriteRule) strips(name string) bool {
+	for _, ptn := range r.StripQuery {
+		if ok, _ := path.Match(ptn, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// delHeader deletes name case-insensitively, devtools headers are not canonical, e.g. http/2 ones are lower case.
+func delHeader(header http.Header, name string) {
+	for k := range header {
+		if strings.EqualFold(k, name) {
+			delete(header, k)
+		}
+	}
+}
+
+func fetchHeaderEntries(header http.Header) []*proto.FetchHeaderEntry {
+	entries := []*proto.FetchHeaderEntry{}
+
+	for name, values := range header {
+		for _, v := range values {
+			entries = append(entries, &proto.FetchHeaderEntry{Name: name, Value: v})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries
+}
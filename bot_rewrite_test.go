@@ -0,0 +1,100 @@
+package wee
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/coghost/wee/fixtures"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/suite"
+)
+
+type RewriteSuite struct {
+	suite.Suite
+}
+
+func TestRewrite(t *testing.T) {
+	suite.Run(t, new(RewriteSuite))
+}
+
+func (s *RewriteSuite) newRequest(uri string) *http.Request {
+	req, err := http.NewRequest(http.MethodGet, uri, nil)
+	s.Require().NoError(err)
+
+	// devtools headers are not canonical.
+	req.Header = http.Header{"cookie": {"a=1"}, "user-agent": {"ua"}}
+
+	return req
+}
+
+func (s *RewriteSuite) TestApply() {
+	rr, err := newRewriteRule(RewriteRule{
+		Regex:         `^https://cdn\.example\.com/(.*)$`,
+		URL:           "http://127.0.0.1:8080/$1",
+		SetHeaders:    map[string]string{"User-Agent": "wee", "X-Token": "t"},
+		RemoveHeaders: []string{"Cookie"},
+		StripQuery:    []string{"utm_*", "fbclid"},
+		Method:        http.MethodPost,
+		Body:          []byte("q=1"),
+	})
+	s.Require().NoError(err)
+
+	req := s.newRequest("https://cdn.example.com/js/app.js?v=2&utm_source=x&fbclid=y#top")
+	cq := &proto.FetchContinueRequest{}
+	rr.apply(req, cq)
+
+	s.Equal("http://127.0.0.1:8080/js/app.js?v=2#top", cq.URL)
+	s.Equal("127.0.0.1:8080", req.Host)
+	s.Equal(http.MethodPost, cq.Method)
+	s.Equal([]byte("q=1"), cq.PostData)
+
+	body, err := io.ReadAll(req.Body)
+	s.Require().NoError(err)
+	s.Equal("q=1", string(body))
+
+	s.Equal([]*proto.FetchHeaderEntry{
+		{Name: "User-Agent", Value: "wee"},
+		{Name: "X-Token", Value: "t"},
+	}, cq.Headers)
+}
+
+func (s *RewriteSuite) TestApplyUnchanged() {
+	rr, err := newRewriteRule(RewriteRule{StripQuery: []string{"utm_*"}})
+	s.Require().NoError(err)
+
+	req := s.newRequest("https://example.com/?a=%20b&c=1")
+	cq := &proto.FetchContinueRequest{}
+	rr.apply(req, cq)
+
+	s.Equal(&proto.FetchContinueRequest{}, cq, "nothing to change, original request is sent")
+}
+
+func (s *RewriteSuite) TestInvalid() {
+	_, err := newRewriteRule(RewriteRule{Regex: "("})
+	s.ErrorIs(err, ErrInvalidRewriteRule)
+
+	_, err = newRewriteRule(RewriteRule{StripQuery: []string{"["}})
+	s.ErrorIs(err, ErrInvalidRewriteRule)
+}
+
+func (s *RewriteSuite) TestBot() {
+	ts := fixtures.NewTestServer()
+	defer ts.Close()
+
+	bot := NewBotHeadless()
+	defer bot.Cleanup()
+
+	h := bot.MustAddRewriteRule(
+		RewriteRule{Pattern: "*/headers", SetHeaders: map[string]string{"X-Wee": "rewritten"}},
+		RewriteRule{Regex: `/api/data$`, URL: "/user_agent"},
+	)
+	defer h.Remove()
+
+	bot.MustOpen(ts.URL + "/headers")
+	s.Contains(bot.page.MustElement("body").MustText(), "X-Wee: rewritten")
+
+	got := bot.page.MustEval(`() => fetch('/api/data').then(r => r.text())`).Str()
+	s.NotContains(got, "XHR response", "served by /user_agent")
+	s.Contains(got, "Mozilla")
+}
@@ -23,11 +23,11 @@ var ErrElemShapeBox = errors.New("cannot get element box by shape.Box()")
 
 // PressPageDown simulates pressing the Page Down key multiple times on the main document body.
 //   - It takes an integer parameter 'times' specifying the number of times to press Page Down.
-//   - It takes `html>body` as the element to perform page down
+//   - It takes `html>body` as the element to perform page down, waiting up to the bot's long timeout.
 //
 // Returns an error if the body element cannot be found or if the key press operation fails.
 func (b *Bot) PressPageDown(times int) error {
-	elem, err := b.Elem("html>body", WithTimeout(PT60Sec))
+	elem, err := b.Elem("html>body", WithTimeout(b.longTimeout.Seconds()))
 	if err != nil {
 		return err
 	}
@@ -42,7 +42,9 @@ func (b *Bot) PressPageDownOnElem(elem *rod.Element, times int) error {
 			return err
 		}
 
-		time.Sleep(200 * time.Millisecond) //nolint:mnd
+		if err := b.randSleep(0.2, 0.2); err != nil { //nolint:mnd
+			return err
+		}
 	}
 
 	return nil
@@ -145,14 +147,14 @@ func (b *Bot) ScrollLikeHuman(offsetX, offsetY float64, opts ...ElemOptionFunc)
 	enabled := opt.scrollAsHuman.enabled || opt.humanized
 
 	if !enabled || steps == 0 {
-		err := b.Scroll(offsetX, offsetY, steps)
-
-		SleepPT100Ms()
+		if err := b.Scroll(offsetX, offsetY, steps); err != nil {
+			return err
+		}
 
-		return err
+		return b.sleepPT100Ms()
 	}
 
-	tooSlowTimeoutSec := 60.0
+	tooSlowTimeoutSec := b.longTimeout.Seconds()
 	totalScrolled := 0.0
 	totalOffsetNeeded := offsetY
 
@@ -169,26 +171,36 @@ func (b *Bot) ScrollLikeHuman(offsetX, offsetY float64, opts ...ElemOptionFunc)
 	)
 
 	for totalScrolled < totalOffsetNeeded {
+		if err := b.Context().Err(); err != nil {
+			return err
+		}
+
 		yNegative := false
 		// handle too slow scroll
 		cost := time.Since(startAt).Seconds()
 		if cost > tooSlowTimeoutSec {
-			err := b.Scroll(offsetX, totalOffsetNeeded-totalScrolled, 1)
-
-			SleepPT100Ms()
+			if err := b.Scroll(offsetX, totalOffsetNeeded-totalScrolled, 1); err != nil {
+				return err
+			}
 
-			return err
+			return b.sleepPT100Ms()
 		}
 
 		chance := rand.Float64()
 
 		if chance < opt.scrollAsHuman.longSleepChance {
-			RandSleepNap()
+			if err := b.sleepNap(); err != nil {
+				return err
+			}
+
 			continue
 		}
 
 		if chance < opt.scrollAsHuman.shortSleepChance {
-			SleepPT500Ms()
+			if err := b.sleepPT500Ms(); err != nil {
+				return err
+			}
+
 			continue
 		}
 
@@ -0,0 +1,257 @@
+package wee
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/gookit/goutil/fsutil"
+	"go.uber.org/zap"
+)
+
+const (
+	_jsReadWebStorage = `() => {
+  const dump = (s) => Object.keys(s).map((k) => ({ name: k, value: s.getItem(k) }))
+  return { origin: location.origin, localStorage: dump(localStorage), sessionStorage: dump(sessionStorage) }
+}`
+	_jsWriteWebStorage = `(local, session) => {
+  for (const i of local || []) localStorage.setItem(i.name, i.value)
+  for (const i of session || []) sessionStorage.setItem(i.name, i.value)
+}`
+	// _opaqueOrigin is location.origin of pages like about:blank.
+	_opaqueOrigin = "null"
+)
+
+var ErrMissingStorageStateFile = errors.New("missing storage state file")
+
+// StorageState is a snapshot of a browser session, similar to Playwright's storageState:
+// all cookies of the browser plus the web storage of every origin saved so far.
+type StorageState struct {
+	Cookies []proto.NetworkCookie `json:"cookies"`
+	Origins []*OriginStorage      `json:"origins"`
+}
+
+// OriginStorage is the localStorage and sessionStorage of one origin.
+type OriginStorage struct {
+	Origin         string        `json:"origin"`
+	LocalStorage   []StorageItem `json:"localStorage"`
+	SessionStorage []StorageItem `json:"sessionStorage,omitempty"`
+}
+
+type StorageItem struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// WithStorageState restores cookies and web storage from the file on MustOpen,
+// the file is usually created by SaveStorageState.
+func WithStorageState(path string) BotOption {
+	return func(o *Bot) {
+		o.storageStateFile = path
+	}
+}
+
+// StorageStateFile returns the file set by WithStorageState.
+func (b *Bot) StorageStateFile() string {
+	return b.storageStateFile
+}
+
+// SaveStorageState saves all cookies of the browser and the web storage of current page into path,
+// the file is encrypted the same way as DumpCookies if a cookie key is set.
+//
+// Web storage can only be read from the page's own origin, so if path already exists,
+// origins saved before are kept and current origin is replaced.
+// This makes it possible to build one state file by visiting several sites in turn.
+func (b *Bot) SaveStorageState(path string) error {
+	state, err := b.LoadStorageState(path)
+	if err != nil {
+		if !errors.Is(err, ErrMissingStorageStateFile) {
+			return err
+		}
+
+		state = &StorageState{}
+	}
+
+	cookies, err := b.browser.GetCookies()
+	if err != nil {
+		return fmt.Errorf("cannot get cookies: %w", err)
+	}
+
+	state.Cookies = state.Cookies[:0]
+	for _, c := range cookies {
+		state.Cookies = append(state.Cookies, *c)
+	}
+
+	origin, err := b.currentWebStorage()
+	if err != nil {
+		return err
+	}
+
+	if origin != nil {
+		state.setOrigin(origin)
+	}
+
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal storage state: %w", err)
+	}
+
+	if err := fsutil.MkParentDir(path); err != nil {
+		return err
+	}
+
+	if err := b.writeCookieFile(path, content); err != nil {
+		return fmt.Errorf("cannot save file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadStorageState reads a state file saved by SaveStorageState,
+// an encrypted file is decrypted with the key of CookieKeyEnv, see Bot.LoadStorageState for WithCookieKey.
+func LoadStorageState(path string) (*StorageState, error) {
+	return loadStorageState(path, []byte(os.Getenv(CookieKeyEnv)))
+}
+
+// LoadStorageState reads a state file saved by SaveStorageState, decrypting it with the bot's cookie key if needed.
+func (b *Bot) LoadStorageState(path string) (*StorageState, error) {
+	return loadStorageState(path, b.cookieKey)
+}
+
+func loadStorageState(path string, key []byte) (*StorageState, error) {
+	if !fsutil.FileExist(path) {
+		return nil, fmt.Errorf("%w: %s", ErrMissingStorageStateFile, path)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err = decryptCookies(key, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var state StorageState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal storage state: %w", err)
+	}
+
+	return &state, nil
+}
+
+// RestoreStorageState restores cookies and web storage from the file set by WithStorageState.
+//
+// typically with following steps:
+//   - open it's origin `https://xxx.com`, web storage can only be written there
+//   - set all cookies to browser
+//   - write localStorage/sessionStorage saved for this origin
+//
+// A missing state file is not an error, it happens on the first run before anything is saved.
+func (b *Bot) RestoreStorageState(uri string) error {
+	state, err := b.LoadStorageState(b.storageStateFile)
+	if err != nil {
+		if errors.Is(err, ErrMissingStorageStateFile) {
+			b.logger.Info("skip restoring storage state", zap.Error(err))
+			return nil
+		}
+
+		return err
+	}
+
+	up, err := url.Parse(uri)
+	if err != nil {
+		return err
+	}
+
+	homepage := fmt.Sprintf("%s://%s", up.Scheme, up.Host)
+	if err := b.Open(homepage); err != nil {
+		return err
+	}
+
+	return b.ApplyStorageState(state)
+}
+
+// ApplyStorageState sets all cookies of state to the browser,
+// and writes the web storage saved for the origin of current page.
+func (b *Bot) ApplyStorageState(state *StorageState) error {
+	if len(state.Cookies) != 0 {
+		nodes := make([]*proto.NetworkCookieParam, 0, len(state.Cookies))
+		for _, c := range state.Cookies {
+			nodes = append(nodes, CookieToParam(c, ""))
+		}
+
+		if err := b.browser.SetCookies(nodes); err != nil {
+			return fmt.Errorf("cannot set cookies: %w", err)
+		}
+	}
+
+	origin := state.origin(b.currentOrigin())
+	if origin == nil {
+		return nil
+	}
+
+	_, err := b.page.Timeout(b.mediumTimeout).Eval(_jsWriteWebStorage, origin.LocalStorage, origin.SessionStorage)
+	if err != nil {
+		return fmt.Errorf("cannot write web storage: %w", err)
+	}
+
+	b.logger.Debug("restored web storage",
+		zap.String("origin", origin.Origin),
+		zap.Int("local", len(origin.LocalStorage)),
+		zap.Int("session", len(origin.SessionStorage)))
+
+	return nil
+}
+
+func (b *Bot) currentWebStorage() (*OriginStorage, error) {
+	obj, err := b.page.Timeout(b.mediumTimeout).Eval(_jsReadWebStorage)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read web storage: %w", err)
+	}
+
+	var origin OriginStorage
+	if err := obj.Value.Unmarshal(&origin); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal web storage: %w", err)
+	}
+
+	if origin.Origin == "" || origin.Origin == _opaqueOrigin {
+		return nil, nil
+	}
+
+	return &origin, nil
+}
+
+func (b *Bot) currentOrigin() string {
+	u, err := url.Parse(b.CurrentURL())
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+}
+
+func (s *StorageState) origin(name string) *OriginStorage {
+	for _, o := range s.Origins {
+		if o.Origin == name {
+			return o
+		}
+	}
+
+	return nil
+}
+
+func (s *StorageState) setOrigin(origin *OriginStorage) {
+	for i, o := range s.Origins {
+		if o.Origin == origin.Origin {
+			s.Origins[i] = origin
+			return
+		}
+	}
+
+	s.Origins = append(s.Origins, origin)
+}
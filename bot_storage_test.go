@@ -0,0 +1,79 @@
+package wee
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/coghost/wee/fixtures"
+	"github.com/stretchr/testify/suite"
+)
+
+type BotStorageSuite struct {
+	suite.Suite
+	ts *httptest.Server
+}
+
+func TestBotStorage(t *testing.T) {
+	suite.Run(t, new(BotStorageSuite))
+}
+
+func (s *BotStorageSuite) SetupSuite() {
+	s.ts = fixtures.NewTestServer()
+}
+
+func (s *BotStorageSuite) TearDownSuite() {
+	s.ts.Close()
+}
+
+func (s *BotStorageSuite) TestSaveAndRestore() {
+	file := filepath.Join(s.T().TempDir(), "state.json")
+
+	bot := NewBotHeadless()
+	bot.MustOpen(s.ts.URL + "/set_cookie")
+	bot.MustEval(`() => { localStorage.setItem("token", "jwt.a.b"); sessionStorage.setItem("tab", "1") }`)
+	s.Require().NoError(bot.SaveStorageState(file))
+	bot.Cleanup()
+
+	state, err := bot.LoadStorageState(file)
+	s.Require().NoError(err)
+	s.Len(state.Origins, 1)
+	s.Equal([]StorageItem{{Name: "token", Value: "jwt.a.b"}}, state.Origins[0].LocalStorage)
+
+	restored := NewBotHeadless(WithStorageState(file))
+	defer restored.Cleanup()
+
+	restored.MustOpen(s.ts.URL + "/check_cookie")
+	s.Contains(restored.MustElemAttr("body"), "sessionid=sessionid001")
+	s.Equal("jwt.a.b", restored.MustEval(`() => localStorage.getItem("token")`))
+	s.Equal("1", restored.MustEval(`() => sessionStorage.getItem("tab")`))
+}
+
+func (s *BotStorageSuite) TestMissingStateFile() {
+	bot := NewBotHeadless(WithStorageState(filepath.Join(s.T().TempDir(), "none.json")))
+	defer bot.Cleanup()
+
+	s.NotPanics(func() {
+		bot.MustOpen(s.ts.URL)
+	})
+}
+
+func (s *BotStorageSuite) TestLoadStorageState() {
+	file := filepath.Join(s.T().TempDir(), "state.json")
+
+	bot := &Bot{cookieKey: []byte(_testCookieKey)}
+	s.Require().NoError(bot.writeCookieFile(file, []byte(`{"cookies":[],"origins":[{"origin":"https://a.com"}]}`)))
+
+	state, err := bot.LoadStorageState(file)
+	s.Require().NoError(err)
+	s.Equal("https://a.com", state.Origins[0].Origin)
+
+	s.T().Setenv(CookieKeyEnv, _testCookieKey)
+
+	state, err = LoadStorageState(file)
+	s.Require().NoError(err)
+	s.Equal("https://a.com", state.Origins[0].Origin)
+
+	_, err = LoadStorageState(filepath.Join(s.T().TempDir(), "missing.json"))
+	s.ErrorIs(err, ErrMissingStorageStateFile)
+}
@@ -0,0 +1,311 @@
+package wee
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// StreamEventType is the type of a StreamEvent.
+type StreamEventType string
+
+const (
+	StreamWebSocketCreated  StreamEventType = "ws_created"
+	StreamWebSocketSent     StreamEventType = "ws_sent"
+	StreamWebSocketReceived StreamEventType = "ws_received"
+	StreamWebSocketError    StreamEventType = "ws_error"
+	StreamWebSocketClosed   StreamEventType = "ws_closed"
+	// StreamSSEMessage is a message of Server-Sent Events (EventSource).
+	StreamSSEMessage StreamEventType = "sse_message"
+)
+
+var ErrNilStreamHandler = errors.New("nil stream handler")
+
+// _wsOpcodeBinary is the websocket opcode of binary frames, devtools sends their payload in base64.
+const _wsOpcodeBinary = 2
+
+// StreamEvent is a WebSocket or Server-Sent Events event of the bot's page.
+type StreamEvent struct {
+	Type StreamEventType
+	// Time is when the event is received by the bot.
+	Time      time.Time
+	RequestID string
+	// URL of the websocket or event source.
+	URL string
+
+	// Opcode of websocket frames, 1 is text and 2 is binary.
+	Opcode int
+	// Data is the text of websocket frames or SSE messages, base64 for binary frames.
+	Data string
+
+	// EventName and EventID of SSE messages.
+	EventName string
+	EventID   string
+
+	// Error of StreamWebSocketError.
+	Error string
+}
+
+// Payload returns the data, binary frames are decoded.
+func (e *StreamEvent) Payload() ([]byte, error) {
+	if e.Opcode == _wsOpcodeBinary {
+		return base64.StdEncoding.DecodeString(e.Data)
+	}
+
+	return []byte(e.Data), nil
+}
+
+// JSON unmarshals the payload into v.
+func (e *StreamEvent) JSON(v any) error {
+	payload, err := e.Payload()
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(payload, v)
+}
+
+type StreamOptions struct {
+	patterns []string
+	types    []StreamEventType
+}
+
+type StreamOptionFunc func(o *StreamOptions)
+
+func bindStreamOptions(opt *StreamOptions, opts ...StreamOptionFunc) {
+	for _, f := range opts {
+		f(opt)
+	}
+}
+
+// WithStreamPatterns only emits events of websockets or event sources whose url matches any of patterns.
+func WithStreamPatterns(patterns ...string) StreamOptionFunc {
+	return func(o *StreamOptions) {
+		o.patterns = patterns
+	}
+}
+
+// WithStreamTypes only emits events of types, default is all.
+func WithStreamTypes(types ...StreamEventType) StreamOptionFunc {
+	return func(o *StreamOptions) {
+		o.types = types
+	}
+}
+
+// StreamSubscription is returned by SubscribeStreams.
+type StreamSubscription struct {
+	cancel  context.CancelFunc
+	done    chan struct{}
+	handler func(*StreamEvent)
+
+	patterns []*regexp.Regexp
+	types    map[StreamEventType]bool
+
+	mu sync.Mutex
+	// urls of matched websockets and event sources by request id.
+	urls map[proto.NetworkRequestID]string
+}
+
+// SubscribeStreams calls handler with WebSocket and Server-Sent Events events of the bot's page,
+// which Hijack and DumpXHR cannot see.
+//
+// Events are emitted in the order received, handler is called in one goroutine and should not block long.
+// Only streams opened after subscribing are seen.
+//
+// Usage:
+//
+//	sub, err := bot.SubscribeStreams(func(e *wee.StreamEvent) {
+//	    var tick Tick
+//	    if e.Type == wee.StreamWebSocketReceived && e.JSON(&tick) == nil {
+//	        ticks <- tick
+//	    }
+//	}, wee.WithStreamPatterns("*feed.example.com*"))
+//	defer sub.Unsubscribe()
+//
+//	bot.MustOpen(uri)
+func (b *Bot) SubscribeStreams(handler func(*StreamEvent), opts ...StreamOptionFunc) (*StreamSubscription, error) {
+	sub, err := newStreamSubscription(handler, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub.cancel = cancel
+	sub.done = make(chan struct{})
+
+	wait := b.page.Context(ctx).EachEvent(
+		sub.onWebSocketCreated,
+		sub.onWebSocketSent,
+		sub.onWebSocketReceived,
+		sub.onWebSocketError,
+		sub.onWebSocketClosed,
+		sub.onRequest,
+		sub.onSSEMessage,
+		func(e *proto.NetworkLoadingFinished) { sub.forget(e.RequestID) },
+		func(e *proto.NetworkLoadingFailed) { sub.forget(e.RequestID) },
+	)
+
+	go func() {
+		defer close(sub.done)
+		wait()
+	}()
+
+	return sub, nil
+}
+
+func (b *Bot) MustSubscribeStreams(handler func(*StreamEvent), opts ...StreamOptionFunc) *StreamSubscription {
+	sub, err := b.SubscribeStreams(handler, opts...)
+	b.pie(err)
+
+	return sub
+}
+
+func newStreamSubscription(handler func(*StreamEvent), opts ...StreamOptionFunc) (*StreamSubscription, error) {
+	if handler == nil {
+		return nil, ErrNilStreamHandler
+	}
+
+	opt := &StreamOptions{}
+	bindStreamOptions(opt, opts...)
+
+	sub := &StreamSubscription{
+		handler: handler,
+		urls:    make(map[proto.NetworkRequestID]string),
+	}
+
+	for _, ptn := range opt.patterns {
+		reg, err := regexp.Compile(proto.PatternToReg(ptn))
+		if err != nil {
+			return nil, fmt.Errorf("invalid stream pattern %q: %w", ptn, err)
+		}
+
+		sub.patterns = append(sub.patterns, reg)
+	}
+
+	if len(opt.types) != 0 {
+		sub.types = make(map[StreamEventType]bool)
+		for _, t := range opt.types {
+			sub.types[t] = true
+		}
+	}
+
+	return sub, nil
+}
+
+// Unsubscribe stops emitting events, it's safe to call more than once.
+func (s *StreamSubscription) Unsubscribe() {
+	if s == nil || s.cancel == nil {
+		return
+	}
+
+	s.cancel()
+	<-s.done
+}
+
+// track remembers the stream if url matches.
+func (s *StreamSubscription) track(id proto.NetworkRequestID, uri string) bool {
+	if len(s.patterns) != 0 {
+		matched := false
+
+		for _, reg := range s.patterns {
+			if reg.MatchString(uri) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	s.mu.Lock()
+	s.urls[id] = uri
+	s.mu.Unlock()
+
+	return true
+}
+
+func (s *StreamSubscription) url(id proto.NetworkRequestID) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uri, ok := s.urls[id]
+
+	return uri, ok
+}
+
+func (s *StreamSubscription) forget(id proto.NetworkRequestID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.urls, id)
+}
+
+// emit calls handler if the stream of id is tracked and typ is wanted.
+func (s *StreamSubscription) emit(typ StreamEventType, id proto.NetworkRequestID, event *StreamEvent) {
+	uri, ok := s.url(id)
+	if !ok || (s.types != nil && !s.types[typ]) {
+		return
+	}
+
+	event.Type = typ
+	event.Time = time.Now()
+	event.RequestID = string(id)
+	event.URL = uri
+
+	s.handler(event)
+}
+
+func (s *StreamSubscription) onWebSocketCreated(e *proto.NetworkWebSocketCreated) {
+	if s.track(e.RequestID, e.URL) {
+		s.emit(StreamWebSocketCreated, e.RequestID, &StreamEvent{})
+	}
+}
+
+func (s *StreamSubscription) onWebSocketSent(e *proto.NetworkWebSocketFrameSent) {
+	s.emit(StreamWebSocketSent, e.RequestID, frameEvent(e.Response))
+}
+
+func (s *StreamSubscription) onWebSocketReceived(e *proto.NetworkWebSocketFrameReceived) {
+	s.emit(StreamWebSocketReceived, e.RequestID, frameEvent(e.Response))
+}
+
+func (s *StreamSubscription) onWebSocketError(e *proto.NetworkWebSocketFrameError) {
+	s.emit(StreamWebSocketError, e.RequestID, &StreamEvent{Error: e.ErrorMessage})
+}
+
+func (s *StreamSubscription) onWebSocketClosed(e *proto.NetworkWebSocketClosed) {
+	s.emit(StreamWebSocketClosed, e.RequestID, &StreamEvent{})
+	s.forget(e.RequestID)
+}
+
+// onRequest tracks event sources, SSE messages only have the request id.
+func (s *StreamSubscription) onRequest(e *proto.NetworkRequestWillBeSent) {
+	if e.Type == proto.NetworkResourceTypeEventSource {
+		s.track(e.RequestID, e.Request.URL)
+	}
+}
+
+func (s *StreamSubscription) onSSEMessage(e *proto.NetworkEventSourceMessageReceived) {
+	s.emit(StreamSSEMessage, e.RequestID, &StreamEvent{
+		Data:      e.Data,
+		EventName: e.EventName,
+		EventID:   e.EventID,
+	})
+}
+
+func frameEvent(frame *proto.NetworkWebSocketFrame) *StreamEvent {
+	if frame == nil {
+		return &StreamEvent{}
+	}
+
+	return &StreamEvent{Opcode: int(frame.Opcode), Data: frame.PayloadData}
+}
@@ -0,0 +1,112 @@
+package wee
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coghost/wee/fixtures"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/stretchr/testify/suite"
+)
+
+type StreamSuite struct {
+	suite.Suite
+}
+
+func TestStream(t *testing.T) {
+	suite.Run(t, new(StreamSuite))
+}
+
+func (s *StreamSuite) TestEmit() {
+	var events []*StreamEvent
+
+	sub, err := newStreamSubscription(func(e *StreamEvent) {
+		events = append(events, e)
+	}, WithStreamPatterns("wss://feed.example.com/*"))
+	s.Require().NoError(err)
+
+	sub.onWebSocketCreated(&proto.NetworkWebSocketCreated{RequestID: "1", URL: "wss://feed.example.com/live"})
+	sub.onWebSocketCreated(&proto.NetworkWebSocketCreated{RequestID: "2", URL: "wss://chat.example.com/"})
+
+	sub.onWebSocketSent(&proto.NetworkWebSocketFrameSent{RequestID: "1", Response: &proto.NetworkWebSocketFrame{Opcode: 1, PayloadData: `{"sub":"btc"}`}})
+	sub.onWebSocketReceived(&proto.NetworkWebSocketFrameReceived{RequestID: "1", Response: &proto.NetworkWebSocketFrame{Opcode: 2, PayloadData: "eyJwIjoxfQ=="}})
+	sub.onWebSocketReceived(&proto.NetworkWebSocketFrameReceived{RequestID: "2", Response: &proto.NetworkWebSocketFrame{Opcode: 1, PayloadData: "ignored"}})
+	sub.onWebSocketError(&proto.NetworkWebSocketFrameError{RequestID: "1", ErrorMessage: "bad frame"})
+	sub.onWebSocketClosed(&proto.NetworkWebSocketClosed{RequestID: "1"})
+	sub.onWebSocketReceived(&proto.NetworkWebSocketFrameReceived{RequestID: "1", Response: &proto.NetworkWebSocketFrame{Opcode: 1, PayloadData: "after close"}})
+
+	s.Require().Len(events, 5)
+
+	types := make([]StreamEventType, 0, len(events))
+	for _, e := range events {
+		types = append(types, e.Type)
+		s.Equal("wss://feed.example.com/live", e.URL)
+	}
+
+	s.Equal([]StreamEventType{
+		StreamWebSocketCreated, StreamWebSocketSent, StreamWebSocketReceived, StreamWebSocketError, StreamWebSocketClosed,
+	}, types)
+
+	var got struct{ P int }
+	s.Require().NoError(events[2].JSON(&got), "binary frame is decoded")
+	s.Equal(1, got.P)
+	s.Equal("bad frame", events[3].Error)
+}
+
+func (s *StreamSuite) TestTypes() {
+	var events []*StreamEvent
+
+	sub, err := newStreamSubscription(func(e *StreamEvent) {
+		events = append(events, e)
+	}, WithStreamTypes(StreamSSEMessage))
+	s.Require().NoError(err)
+
+	sub.onRequest(&proto.NetworkRequestWillBeSent{RequestID: "1", Type: proto.NetworkResourceTypeEventSource, Request: &proto.NetworkRequest{URL: "http://a.com/sse"}})
+	sub.onRequest(&proto.NetworkRequestWillBeSent{RequestID: "2", Type: proto.NetworkResourceTypeXHR, Request: &proto.NetworkRequest{URL: "http://a.com/api"}})
+	sub.onSSEMessage(&proto.NetworkEventSourceMessageReceived{RequestID: "1", EventName: "tick", EventID: "7", Data: "x"})
+	sub.onSSEMessage(&proto.NetworkEventSourceMessageReceived{RequestID: "2", Data: "not an event source"})
+	sub.onWebSocketCreated(&proto.NetworkWebSocketCreated{RequestID: "3", URL: "ws://a.com/"})
+
+	s.Require().Len(events, 1)
+	s.Equal(&StreamEvent{
+		Type: StreamSSEMessage, Time: events[0].Time, RequestID: "1", URL: "http://a.com/sse",
+		Data: "x", EventName: "tick", EventID: "7",
+	}, events[0])
+
+	_, err = newStreamSubscription(nil)
+	s.ErrorIs(err, ErrNilStreamHandler)
+}
+
+func (s *StreamSuite) TestBotSSE() {
+	ts := fixtures.NewTestServer()
+	defer ts.Close()
+
+	bot := NewBotHeadless()
+	defer bot.Cleanup()
+
+	var (
+		mu   sync.Mutex
+		data []string
+	)
+
+	sub := bot.MustSubscribeStreams(func(e *StreamEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		data = append(data, e.Data)
+	}, WithStreamPatterns("*/sse"))
+	defer sub.Unsubscribe()
+
+	bot.MustOpen(ts.URL + "/sse_test")
+	bot.page.MustElement(`#messages li:nth-child(3)`)
+
+	s.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(data) == 3
+	}, 3*time.Second, 100*time.Millisecond)
+
+	s.Equal([]string{`{"n": 1}`, `{"n": 2}`, `{"n": 3}`}, data)
+}
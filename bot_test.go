@@ -5,8 +5,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
+	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
@@ -134,6 +136,36 @@ func (s *BotSuite) Test00BotWithOptionsOnly() {
 	s.Equal(3, bot.highlightTimes)
 }
 
+func (s *BotSuite) Test00BotWithOptionsOnlyE() {
+	s.T().Parallel()
+
+	bot, err := NewBotE(WithPage(false), Headless(true))
+	s.Require().NoError(err)
+
+	defer bot.Cleanup()
+
+	s.Nil(bot.page)
+	s.Require().NoError(BindBotLauncherE(bot))
+	s.NotNil(bot.page)
+
+	s.NoError(bot.Open(s.ts.URL))
+}
+
+func (s *BotSuite) Test00NewBotKeepsBrowserPassedIn() {
+	l, brw := NewBrowser(BrowserHeadless(true))
+	defer closeLaunched(l, brw)
+
+	// the cache dir is a file, so the page customization fails.
+	file := s.T().TempDir() + "/cache"
+	s.Require().NoError(os.WriteFile(file, nil, 0o600))
+
+	_, err := NewBotE(Launcher(l), Browser(brw), WithResponseCache(file, nil))
+	s.ErrorIs(err, ErrCreatePageFailed)
+
+	_, err = brw.Pages()
+	s.NoError(err, "the browser passed in is left to the caller")
+}
+
 func (s *BotSuite) Test00BotUserMode() {
 	s.T().Parallel()
 
@@ -257,6 +289,34 @@ func (s *BotSuite) Test02CopyAsCURLCookies() {
 	}
 }
 
+func (s *BotSuite) Test02CookieJar() {
+	s.T().Parallel()
+
+	bot := NewBotHeadless()
+	defer bot.Cleanup()
+
+	bot.MustOpen(s.ts.URL + "/set_cookie")
+
+	jar, err := bot.NewCookieJar()
+	s.Require().NoError(err)
+
+	resp, err := (&http.Client{Jar: jar}).Get(s.ts.URL + "/check_cookie")
+	s.Require().NoError(err)
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.ElementsMatch(strings.Split(_testCkStr, "; "), strings.Split(string(body), "; "))
+
+	other := NewBotHeadless()
+	defer other.Cleanup()
+
+	s.Require().NoError(other.ImportCookiesFromJar(jar, s.ts.URL))
+	other.MustOpen(s.ts.URL + "/check_cookie")
+	s.ElementsMatch(strings.Split(_testCkStr, "; "), strings.Split(other.page.MustElement(`pre`).MustText(), "; "))
+}
+
 func (s *BotSuite) Test02DumpCookies() {
 	s.T().Parallel()
 
@@ -443,6 +503,20 @@ func (s *BotSuite) Test03ProxyServerByMitm() {
 				return NewBrowser(BrowserExtensions(extPth, _testExtension))
 			},
 		},
+		{
+			name:    "with mv3 proxy extension",
+			home:    _mitmHTTPHome,
+			locator: httploc,
+			newBrw: func() (*launcher.Launcher, *rod.Browser) {
+				cfg, err := ParseProxyConfig("ab:cd@" + addrAuth)
+				dry.PanicIfErr(err)
+
+				extPth, err := NewProxyExtension(*cfg, "/tmp")
+				dry.PanicIfErr(err)
+
+				return NewBrowser(BrowserExtensions(extPth, _testExtension))
+			},
+		},
 		{
 			name:    "with non-proxy extension",
 			home:    _mitmHTTPHome,
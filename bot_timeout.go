@@ -0,0 +1,80 @@
+package wee
+
+import "time"
+
+// TimeoutProfile groups all timeouts a bot uses, so they can be scaled together.
+//
+// Methods without an explicit WithTimeout fall back to one of these values:
+//   - Long: page navigation, PressPageDown, the humanized scroll deadline.
+//   - Medium: eval/wait helpers, AnyElem, Input, ClickElemWithScript, WaitURLContains.
+//   - Short: Elem, ElemByText, Click, key presses.
+//   - Nap: Elems when waiting for the first match.
+type TimeoutProfile struct {
+	Long   time.Duration
+	Medium time.Duration
+	Short  time.Duration
+	Nap    time.Duration
+}
+
+// NewTimeoutProfile creates a profile from values in seconds.
+func NewTimeoutProfile(long, medium, short, nap float64) *TimeoutProfile {
+	return &TimeoutProfile{
+		Long:   secToDuration(long),
+		Medium: secToDuration(medium),
+		Short:  secToDuration(short),
+		Nap:    secToDuration(nap),
+	}
+}
+
+// NewTimeoutProfileDefault
+//
+//	@return *TimeoutProfile {60s,20s,10s,2s}
+func NewTimeoutProfileDefault() *TimeoutProfile {
+	return NewTimeoutProfile(LongToSec, MediumToSec, ShortToSec, NapToSec)
+}
+
+// NewTimeoutProfileFast is for local or fixture sites.
+//
+//	@return *TimeoutProfile {20s,5s,3s,1s}
+func NewTimeoutProfileFast() *TimeoutProfile {
+	return NewTimeoutProfile(20, 5, 3, 1) //nolint:mnd
+}
+
+// NewTimeoutProfileSlowProxy is for slow sites behind proxies.
+//
+//	@return *TimeoutProfile {180s,60s,30s,5s}
+func NewTimeoutProfileSlowProxy() *TimeoutProfile {
+	return NewTimeoutProfile(180, 60, 30, 5) //nolint:mnd
+}
+
+// Scale returns a copy of the profile with every timeout multiplied by factor.
+func (p *TimeoutProfile) Scale(factor float64) *TimeoutProfile {
+	return &TimeoutProfile{
+		Long:   time.Duration(float64(p.Long) * factor),
+		Medium: time.Duration(float64(p.Medium) * factor),
+		Short:  time.Duration(float64(p.Short) * factor),
+		Nap:    time.Duration(float64(p.Nap) * factor),
+	}
+}
+
+// WithTimeouts sets the timeout profile, zero values are kept as default.
+func WithTimeouts(profile *TimeoutProfile) BotOption {
+	return func(o *Bot) {
+		o.timeouts = profile
+		o.SetTimeout()
+	}
+}
+
+// Timeouts returns the timeout profile currently used by the bot.
+func (b *Bot) Timeouts() TimeoutProfile {
+	return TimeoutProfile{
+		Long:   b.longTimeout,
+		Medium: b.mediumTimeout,
+		Short:  b.shortTimeout,
+		Nap:    b.napTimeout,
+	}
+}
+
+func secToDuration(sec float64) time.Duration {
+	return time.Duration(sec * float64(time.Second))
+}
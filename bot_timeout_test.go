@@ -0,0 +1,29 @@
+package wee
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTimeouts(t *testing.T) {
+	assert := assert.New(t)
+
+	bot := &Bot{}
+	bot.SetTimeout()
+	assert.Equal(*NewTimeoutProfileDefault(), bot.Timeouts())
+
+	WithTimeouts(NewTimeoutProfileSlowProxy())(bot)
+	assert.Equal(180*time.Second, bot.longTimeout)
+	assert.Equal(5*time.Second, bot.napTimeout)
+
+	// zero values fall back to default
+	WithTimeouts(&TimeoutProfile{Short: time.Second})(bot)
+	assert.Equal(time.Second, bot.shortTimeout)
+	assert.Equal(MediumToSec*time.Second, bot.mediumTimeout)
+
+	scaled := NewTimeoutProfileDefault().Scale(1.5)
+	assert.Equal(90*time.Second, scaled.Long)
+	assert.Equal(3*time.Second, scaled.Nap)
+}
@@ -1,6 +1,7 @@
 package wee
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -21,31 +22,91 @@ const (
 // PaintRects is a flag to show paint rectangles in the browser
 const PaintRects = "--show-paint-rects"
 
+const _existingSessionMsg = "Opening in existing browser session"
+
+var (
+	// ErrLaunchFailed is returned when the browser process cannot be started.
+	ErrLaunchFailed = errors.New("cannot launch browser")
+	// ErrConnectFailed is returned when the devtools endpoint of a launched browser cannot be connected.
+	ErrConnectFailed = errors.New("cannot connect browser")
+	// ErrBrowserSessionBusy is returned in user-mode when chrome is already running with the same profile.
+	ErrBrowserSessionBusy = errors.New("browser is running in an existing session")
+)
+
 func NewUserMode(opts ...BrowserOptionFunc) (*launcher.Launcher, *rod.Browser) {
+	lch, browser, err := NewUserModeE(opts...)
+	if err != nil {
+		log.Fatalf("cannot launch browser: %v", err)
+	}
+
+	return lch, browser
+}
+
+// NewUserModeE is the error-returning version of NewUserMode.
+//
+// Returns ErrBrowserSessionBusy if chrome is already opened with the same user-data-dir,
+// ErrLaunchFailed or ErrConnectFailed for the other failures.
+func NewUserModeE(opts ...BrowserOptionFunc) (*launcher.Launcher, *rod.Browser, error) {
 	opt := BrowserOptions{}
 	bindBrowserOptions(&opt, opts...)
 
-	lch, wsURL := newUserModeLauncher(opts...)
-	browser := rod.New().ControlURL(wsURL).MustConnect().NoDefaultDevice()
+	lch, wsURL, err := newUserModeLauncherE(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	browser := rod.New().ControlURL(wsURL)
+	if err := browser.Connect(); err != nil {
+		lch.Kill()
+		return nil, nil, fmt.Errorf("%w: %w", ErrConnectFailed, err)
+	}
+
+	browser = browser.NoDefaultDevice()
 
 	log.Printf("running in user-mode with user-data-dir:(%s)", opt.userDataDir)
 
-	return lch, browser
+	return lch, browser, nil
 }
 
 func NewBrowser(opts ...BrowserOptionFunc) (*launcher.Launcher, *rod.Browser) {
+	lnchr, brw, err := NewBrowserE(opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return lnchr, brw
+}
+
+// NewBrowserE is the error-returning version of NewBrowser,
+// failures are wrapped with ErrLaunchFailed or ErrConnectFailed.
+func NewBrowserE(opts ...BrowserOptionFunc) (*launcher.Launcher, *rod.Browser, error) {
 	opt := BrowserOptions{slowMotionDelay: SlowMotionMillis, noDefaultDevice: true}
 	bindBrowserOptions(&opt, opts...)
 
 	lnchr := NewLauncher(opts...)
 
-	brw := rod.New().ControlURL(lnchr.MustLaunch()).MustConnect()
+	wsURL, err := lnchr.Launch()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrLaunchFailed, err)
+	}
+
+	brw := rod.New().ControlURL(wsURL)
+	if err := brw.Connect(); err != nil {
+		lnchr.Kill()
+		return nil, nil, fmt.Errorf("%w: %w", ErrConnectFailed, err)
+	}
+
 	if opt.noDefaultDevice {
 		brw.NoDefaultDevice()
 	}
 
 	if opt.incognito {
-		brw.MustIncognito()
+		if _, err := brw.Incognito(); err != nil {
+			_ = brw.Close()
+			lnchr.Kill()
+
+			return nil, nil, fmt.Errorf("%w: cannot create incognito context: %w", ErrConnectFailed, err)
+		}
 	}
 
 	// just ignore cert errors
@@ -55,7 +116,12 @@ func NewBrowser(opts ...BrowserOptionFunc) (*launcher.Launcher, *rod.Browser) {
 
 	brw.SlowMotion(time.Millisecond * time.Duration(opt.slowMotionDelay))
 
-	return lnchr, brw
+	// chrome doesn't accept credentials in --proxy-server, they're answered by the bot, see WithProxyAuth.
+	if _, username, password := splitProxyAuth(opt.proxy); username != "" {
+		_proxyAuths.Store(brw, newProxyAuth(username, password))
+	}
+
+	return lnchr, brw, nil
 }
 
 func NewLauncher(opts ...BrowserOptionFunc) *launcher.Launcher {
@@ -89,13 +155,14 @@ func NewLauncher(opts ...BrowserOptionFunc) *launcher.Launcher {
 	}
 
 	if proxy := opt.proxy; proxy != "" {
-		lnchr.Proxy(proxy)
+		server, _, _ := splitProxyAuth(proxy)
+		lnchr.Proxy(server)
 	}
 
 	return lnchr
 }
 
-func newUserModeLauncher(opts ...BrowserOptionFunc) (*launcher.Launcher, string) {
+func newUserModeLauncherE(opts ...BrowserOptionFunc) (*launcher.Launcher, string, error) {
 	opt := BrowserOptions{}
 	bindBrowserOptions(&opt, opts...)
 
@@ -133,16 +200,18 @@ func newUserModeLauncher(opts ...BrowserOptionFunc) (*launcher.Launcher, string)
 	wsURL, err := launch.Launch()
 	if err != nil {
 		s := fmt.Sprintf("%s", err)
-		if strings.Contains(s, "[launcher] Failed to get the debug url: Opening in existing browser session") {
+		if strings.Contains(s, _existingSessionMsg) {
 			fmt.Printf("%[1]s\nlaunch chrome browser failed, please make sure chrome is closed, and then run again\n%[1]s\n", strings.Repeat("=", 32)) //nolint
+
+			return nil, "", fmt.Errorf("%w: %w", ErrBrowserSessionBusy, err)
 		}
 
-		log.Fatalf("cannot launch browser: %v", err)
+		return nil, "", fmt.Errorf("%w: %w", ErrLaunchFailed, err)
 	}
 
 	log.Printf("got %s", wsURL)
 
-	return launch, wsURL
+	return launch, wsURL, nil
 }
 
 func setLauncher(client *launcher.Launcher, headless bool) {
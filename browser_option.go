@@ -15,7 +15,7 @@ type BrowserOptions struct {
 	slowMotionDelay int
 
 	userDataDir string
-	// proxy ip:port
+	// proxy [scheme://][user:pass@]ip:port
 	proxy string
 
 	flags []string
@@ -75,6 +75,15 @@ func BrowserNoDefaultDevice(b bool) BrowserOptionFunc {
 	}
 }
 
+// BrowserProxy sets the proxy server, e.g. "127.0.0.1:8080", "socks5://127.0.0.1:1080".
+//
+// Credentials of http proxies can be set as "user:pass@host:port", they're escaped if containing "@" or ":",
+// bots using the browser answer the proxy auth challenges, in both headless and headed modes, see WithProxyAuth.
+//
+// Usage:
+//
+//	l, brw := wee.NewBrowser(wee.BrowserProxy("user:pass@proxy.example.com:8000"))
+//	bot := wee.NewBot(wee.Launcher(l), wee.Browser(brw))
 func BrowserProxy(s string) BrowserOptionFunc {
 	return func(o *BrowserOptions) {
 		o.proxy = s
@@ -59,3 +59,13 @@ func (s *BrowserSuite) TestNewBrowser() {
 	// Blocked()
 	SleepN(2)
 }
+
+func (s *BrowserSuite) TestNewBrowserE() {
+	l, brw, err := NewBrowserE(BrowserHeadless(true))
+	s.Require().NoError(err)
+
+	defer l.Cleanup()
+	defer brw.Close()
+
+	brw.MustPage(s.ts.URL)
+}
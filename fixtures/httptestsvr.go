@@ -438,6 +438,92 @@ func newUnstartedTestServer() *httptest.Server {
 		fmt.Fprint(w, "body { background-color: #f0f0f0; }")
 	})
 
+	mux.HandleFunc("/jobs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<!DOCTYPE html>
+<html>
+<head>
+<title>Jobs</title>
+</head>
+<body>
+<span id="total">Showing <b>1 024</b> jobs</span>
+<div class="job">
+  <a class="header" href="/jobs/1">Go Developer</a>
+  <div class="company"><span class="name">Acme</span><span class="city">Stockholm</span></div>
+  <span>Salary: 45 000.50 kr</span>
+  <time datetime="2024-05-01">1 May</time>
+  <ul class="tags"><li>go</li><li>remote</li></ul>
+  <span class="remote">true</span>
+</div>
+<div class="job">
+  <a class="header" href="/jobs/2">Scraper</a>
+  <div class="company"><span class="name">Wee</span></div>
+  <time datetime="yesterday">yesterday</time>
+</div>
+<div class="job">
+  <div class="company"><span class="name">Ghost</span></div>
+</div>
+<a class="next" href="/jobs/page2">next</a>
+</body>
+</html>`))
+	})
+
+	mux.HandleFunc("/jobs/page2", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<!DOCTYPE html>
+<html>
+<head>
+<title>Jobs - 2</title>
+</head>
+<body>
+<div class="job">
+  <a class="header" href="/jobs/3">Tester</a>
+  <div class="company"><span class="name">Bugs</span></div>
+  <span>Salary: 30 000 kr</span>
+</div>
+<a class="next" href="#" aria-disabled="true">next</a>
+</body>
+</html>`))
+	})
+
+	mux.HandleFunc("/sse_test", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `
+        <html>
+            <body>
+                <h1>SSE Test</h1>
+                <ul id="messages"></ul>
+                <script>
+                    const source = new EventSource('/sse');
+                    source.addEventListener('tick', e => {
+                        const li = document.createElement('li');
+                        li.textContent = e.data;
+                        document.getElementById('messages').appendChild(li);
+                        if (e.lastEventId === '3') source.close();
+                    });
+                </script>
+            </body>
+        </html>
+        `)
+	})
+
+	mux.HandleFunc("/sse", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+
+		flusher, _ := w.(http.Flusher)
+
+		for i := 1; i <= 3; i++ {
+			fmt.Fprintf(w, "id: %d\nevent: tick\ndata: {\"n\": %d}\n\n", i, i)
+
+			if flusher != nil {
+				flusher.Flush()
+			}
+
+			time.Sleep(50 * time.Millisecond)
+		}
+	})
+
 	return httptest.NewUnstartedServer(mux)
 }
 
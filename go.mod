@@ -17,7 +17,6 @@ require (
 	github.com/go-rod/stealth v0.4.9
 	github.com/go-vgo/robotgo v0.110.7
 	github.com/gookit/goutil v0.7.0
-	github.com/jrefior/uncurl v0.0.0-20200216180557-8cdd106bc45f
 	github.com/k0kubun/pp/v3 v3.5.0
 	github.com/pterm/pterm v0.12.81
 	github.com/remeh/sizedwaitgroup v1.0.0
@@ -29,6 +28,7 @@ require (
 	github.com/ungerik/go-dry v0.0.0-20231011182423-d9a07fd18c5f
 	github.com/ysmood/gson v0.7.3
 	go.uber.org/zap v1.27.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -129,6 +129,5 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240924160255-9d4c2d233b61 // indirect
 	google.golang.org/grpc v1.67.0 // indirect
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 	gotest.tools/v3 v3.5.1 // indirect
 )
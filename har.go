@@ -0,0 +1,196 @@
+package wee
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	_harVersion     = "1.2"
+	_harCreatorName = "wee"
+)
+
+// HAR is a HTTP Archive 1.2 file, see http://www.softwareishard.com/blog/har-12-spec/
+// Only fields wee records are defined, the file is readable by browsers and HAR viewers.
+type HAR struct {
+	Log *HARLog `json:"log"`
+}
+
+type HARLog struct {
+	Version string      `json:"version"`
+	Creator *HARCreator `json:"creator"`
+	Entries []*HAREntry `json:"entries"`
+}
+
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type HAREntry struct {
+	StartedDateTime string       `json:"startedDateTime"`
+	Time            float64      `json:"time"`
+	Request         *HARRequest  `json:"request"`
+	Response        *HARResponse `json:"response"`
+	Cache           struct{}     `json:"cache"`
+	Timings         *HARTimings  `json:"timings"`
+	ServerIPAddress string       `json:"serverIPAddress,omitempty"`
+	// ResourceType is the devtools resource type, like Chrome's exported HAR.
+	ResourceType string `json:"_resourceType,omitempty"`
+	Comment      string `json:"comment,omitempty"`
+}
+
+type HARRequest struct {
+	Method      string          `json:"method"`
+	URL         string          `json:"url"`
+	HTTPVersion string          `json:"httpVersion"`
+	Cookies     []*HARNameValue `json:"cookies"`
+	Headers     []*HARNameValue `json:"headers"`
+	QueryString []*HARNameValue `json:"queryString"`
+	PostData    *HARPostData    `json:"postData,omitempty"`
+	HeadersSize int             `json:"headersSize"`
+	BodySize    int             `json:"bodySize"`
+}
+
+type HARResponse struct {
+	Status      int             `json:"status"`
+	StatusText  string          `json:"statusText"`
+	HTTPVersion string          `json:"httpVersion"`
+	Cookies     []*HARNameValue `json:"cookies"`
+	Headers     []*HARNameValue `json:"headers"`
+	Content     *HARContent     `json:"content"`
+	RedirectURL string          `json:"redirectURL"`
+	HeadersSize int             `json:"headersSize"`
+	BodySize    int             `json:"bodySize"`
+}
+
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	// Encoding is "base64" for binary text.
+	Encoding string `json:"encoding,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// HARTimings are in milliseconds, -1 means not applicable.
+type HARTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+func newHAR() *HAR {
+	return &HAR{
+		Log: &HARLog{
+			Version: _harVersion,
+			Creator: &HARCreator{Name: _harCreatorName, Version: _harVersion},
+			Entries: []*HAREntry{},
+		},
+	}
+}
+
+// LoadHAR reads a HAR file, e.g. saved by StopHAR or exported from devtools.
+func LoadHAR(path string) (*HAR, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var har HAR
+	if err := json.Unmarshal(raw, &har); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal har: %w", err)
+	}
+
+	if har.Log == nil {
+		return nil, fmt.Errorf("cannot unmarshal har: missing log in %s", path)
+	}
+
+	return &har, nil
+}
+
+// Save writes the HAR into path with owner only permissions, it may contain cookies and auth headers.
+func (h *HAR) Save(path string) error {
+	content, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal har: %w", err)
+	}
+
+	return writeSecretFile(path, content)
+}
+
+// harNameValues converts headers to sorted name/value pairs, multiple values are split by "\n".
+func harNameValues(header http.Header) []*HARNameValue {
+	pairs := []*HARNameValue{}
+
+	for name, values := range header {
+		for _, v := range values {
+			pairs = append(pairs, &HARNameValue{Name: name, Value: v})
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].Name < pairs[j].Name
+	})
+
+	return pairs
+}
+
+func harQueryString(uri string) []*HARNameValue {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return []*HARNameValue{}
+	}
+
+	return harNameValues(http.Header(u.Query()))
+}
+
+func harCookies(header http.Header, key string) []*HARNameValue {
+	cookies := []*HARNameValue{}
+
+	switch key {
+	case "Cookie":
+		for _, c := range (&http.Request{Header: header}).Cookies() {
+			cookies = append(cookies, &HARNameValue{Name: c.Name, Value: c.Value})
+		}
+	case "Set-Cookie":
+		for _, c := range (&http.Response{Header: header}).Cookies() {
+			cookies = append(cookies, &HARNameValue{Name: c.Name, Value: c.Value})
+		}
+	}
+
+	return cookies
+}
+
+// harHTTPVersion converts devtools protocol names like "h2" to "HTTP/2".
+func harHTTPVersion(protocol string) string {
+	switch p := strings.ToLower(protocol); p {
+	case "":
+		return ""
+	case "h2":
+		return "HTTP/2"
+	case "h3":
+		return "HTTP/3"
+	default:
+		return strings.ToUpper(p)
+	}
+}
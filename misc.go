@@ -265,6 +265,14 @@ func FloatAorB(a, b float64) float64 {
 	return b
 }
 
+func durationAorB(a, b time.Duration) time.Duration {
+	if a != 0 {
+		return a
+	}
+
+	return b
+}
+
 // Filenamify converts a string into a valid filename by replacing illegal characters.
 //
 // Parameters:
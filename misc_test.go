@@ -1,7 +1,9 @@
 package wee
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/pterm/pterm"
 	"github.com/stretchr/testify/suite"
@@ -75,3 +77,17 @@ func (s *MiscSuite) TestUniqueName() {
 		s.Equal(tt.want, got, tt.raw)
 	}
 }
+
+func (s *MiscSuite) TestRandSleepCtx() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	_, err := RandSleepCtx(ctx, 1, 2)
+	s.ErrorIs(err, context.Canceled)
+	s.Less(time.Since(start), time.Second)
+
+	slept, err := RandSleepCtx(context.Background(), 0.01, 0.02)
+	s.NoError(err)
+	s.GreaterOrEqual(slept, 10)
+}
@@ -2,6 +2,7 @@
 package wee
 
 import (
+	"context"
 	"math"
 	"math/rand"
 	"time"
@@ -32,6 +33,22 @@ func RandSleep(minSec, maxSec float64, msg ...string) int {
 	return slept
 }
 
+// RandSleepCtx is RandSleep but returns early with ctx.Err() once ctx is done.
+// Returns the planned sleep duration in milliseconds.
+func RandSleepCtx(ctx context.Context, minSec, maxSec float64) (int, error) {
+	slept := RandFloatX1k(minSec, maxSec)
+
+	timer := time.NewTimer(time.Duration(slept) * time.Millisecond)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return slept, ctx.Err()
+	case <-timer.C:
+		return slept, nil
+	}
+}
+
 // SleepN sleeps for a random duration between n and n*1.1 seconds.
 // It returns the actual sleep duration in milliseconds.
 func SleepN(n float64) int {